@@ -0,0 +1,44 @@
+// Package errgrouputil wraps golang.org/x/sync/errgroup so fan-out goroutines keep the request trace
+// It is a separate package so services not using errgroup don't depend on it
+package errgrouputil
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+)
+
+// Group is errgroup.Group, aliased so callers need a single import
+type Group = errgroup.Group
+
+// WithContext is errgroup.WithContext with request tracing carried into the derived context
+// A *gin.Context is converted with httputil.ContextFromGin, so the derived context carries request_id,
+// correlation_id, tenant_id and user_id and follows the cancellation of c.Request. A missing request ID is generated once, so every goroutine
+// logs the same one. The context is canceled the first time a function passed to Go returns
+// an error or Wait returns, exactly like errgroup.
+//
+// Usage:
+//
+//	g, ctx := errgrouputil.WithContext(c)
+//	g.Go(func() error { return h.users.Load(ctx, id) })
+//	g.Go(func() error { return h.orders.Load(ctx, id) })
+//	if err := g.Wait(); err != nil {
+//		httputil.RespondWithError(c, err)
+//		return
+//	}
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	return errgroup.WithContext(tracingContext(ctx))
+}
+
+// tracingContext returns ctx with a stable request ID in the typed context keys
+func tracingContext(ctx context.Context) context.Context {
+	if c, ok := ctx.(*gin.Context); ok {
+		httputil.EnsureRequestID(c)
+		return httputil.ContextFromGin(c)
+	}
+	_, ctx = httputil.EnsureRequestID(ctx)
+	return ctx
+}
@@ -0,0 +1,28 @@
+package grpcutil
+
+import "google.golang.org/grpc/metadata"
+
+// MetadataCarrier adapts gRPC metadata to reqctx.Carrier and reqctx.Extractor
+// Keys are lowercased by metadata.MD, so X-Request-ID maps to MetadataRequestID.
+//
+// Usage:
+//
+//	md := metadata.MD{}
+//	reqctx.Inject(ctx, grpcutil.MetadataCarrier(md))
+//	ctx = metadata.NewOutgoingContext(ctx, md)
+type MetadataCarrier metadata.MD
+
+// Set implements reqctx.Carrier
+func (m MetadataCarrier) Set(key, value string) {
+	metadata.MD(m).Set(key, value)
+}
+
+// Get implements reqctx.Extractor, returning the first non-empty value
+func (m MetadataCarrier) Get(key string) string {
+	for _, value := range metadata.MD(m).Get(key) {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+// Package grpcutil provides gRPC interceptors for request ID propagation
+// It mirrors httputil so the same context carries tracing identifiers across HTTP and gRPC
+package grpcutil
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+const (
+	// MetadataRequestID is the gRPC metadata key for request ID
+	MetadataRequestID = "x-request-id"
+
+	// MetadataCorrelationID is the gRPC metadata key for correlation ID
+	MetadataCorrelationID = "x-correlation-id"
+)
+
+// RequestIDUnaryClientInterceptor attaches the tracing identifiers of ctx to outgoing metadata
+// The identifiers are those httputil sends over HTTP, see httputil.Inject. A *gin.Context works as
+// ctx, the IDs RequestIDMiddleware stored in it are sent without engine.ContextWithFallback.
+// Keys already present in the outgoing metadata are left untouched
+//
+// Usage:
+//
+//	conn, err := grpc.Dial(addr,
+//		grpc.WithUnaryInterceptor(grpcutil.RequestIDUnaryClientInterceptor()),
+//	)
+func RequestIDUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// outgoingContext returns ctx with tracing metadata appended
+// The IDs are resolved by httputil, which reads the gin storage of a *gin.Context; reqctx alone can't see it.
+func outgoingContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	var pairs []string
+	httputil.Inject(ctx, reqctx.CarrierFunc(func(key, value string) {
+		if len(md.Get(key)) == 0 {
+			pairs = append(pairs, key, value)
+		}
+	}))
+
+	if len(pairs) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
@@ -0,0 +1,89 @@
+package grpcutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+func TestRequestIDUnaryClientInterceptor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ginContext := func() *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Set(httputil.RequestIDKey, "abc")
+		c.Set(httputil.CorrelationIDKey, "corr")
+		return c
+	}
+
+	tests := []struct {
+		name            string
+		ctx             func() context.Context
+		wantRequestID   string
+		wantCorrelation string
+	}{
+		{
+			name:            "gin context",
+			ctx:             func() context.Context { return ginContext() },
+			wantRequestID:   "abc",
+			wantCorrelation: "corr",
+		},
+		{
+			name: "gin context through middleware",
+			ctx: func() context.Context {
+				c, _ := gin.CreateTestContext(httptest.NewRecorder())
+				c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+				c.Request.Header.Set(httputil.HeaderRequestID, "from-header")
+				httputil.RequestIDMiddleware()(c)
+				return c
+			},
+			wantRequestID:   "from-header",
+			wantCorrelation: "from-header",
+		},
+		{
+			name: "plain context",
+			ctx: func() context.Context {
+				return reqctx.ContextWithTracing(context.Background(), reqctx.TracingValues{RequestID: "plain", CorrelationID: "flow"})
+			},
+			wantRequestID:   "plain",
+			wantCorrelation: "flow",
+		},
+		{
+			name: "explicit metadata is kept",
+			ctx: func() context.Context {
+				return metadata.AppendToOutgoingContext(ginContext(), MetadataRequestID, "explicit")
+			},
+			wantRequestID:   "explicit",
+			wantCorrelation: "corr",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var md metadata.MD
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				md, _ = metadata.FromOutgoingContext(ctx)
+				return nil
+			}
+
+			interceptor := RequestIDUnaryClientInterceptor()
+			if err := interceptor(tt.ctx(), "/svc/Method", nil, nil, nil, invoker); err != nil {
+				t.Fatal(err)
+			}
+			if got := md.Get(MetadataRequestID); len(got) != 1 || got[0] != tt.wantRequestID {
+				t.Errorf("%s = %v, want [%s]", MetadataRequestID, got, tt.wantRequestID)
+			}
+			if got := md.Get(MetadataCorrelationID); len(got) != 1 || got[0] != tt.wantCorrelation {
+				t.Errorf("%s = %v, want [%s]", MetadataCorrelationID, got, tt.wantCorrelation)
+			}
+		})
+	}
+}
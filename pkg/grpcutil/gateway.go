@@ -0,0 +1,101 @@
+package grpcutil
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// gatewayMetadataPrefix is the prefix of headers runtime.DefaultHeaderMatcher forwards as metadata
+const gatewayMetadataPrefix = "grpc-metadata-"
+
+// GatewayHeaderMatcher maps the IDs of grpc-gateway requests to gRPC metadata
+// The result is a runtime.HeaderMatcherFunc: X-Request-ID, X-Correlation-ID and X-Parent-Request-ID
+// become lowercase metadata keys, so RequestIDUnaryServerInterceptor on the backend sees the IDs of
+// the REST caller. The other tracing headers start side effects or buy service (dry run, priority,
+// risk score, tenant, ...) and are dropped, also as Grpc-Metadata- headers; forward them with
+// GatewayTracingMetadata. Other headers are passed to next, usually runtime.DefaultHeaderMatcher;
+// with a nil next they are dropped. grpcutil does not depend on grpc-gateway, the function
+// signature matches without an import.
+//
+// Usage:
+//
+//	mux := runtime.NewServeMux(
+//		runtime.WithIncomingHeaderMatcher(grpcutil.GatewayHeaderMatcher(runtime.DefaultHeaderMatcher)),
+//	)
+func GatewayHeaderMatcher(next func(key string) (string, bool)) func(key string) (string, bool) {
+	ids := make(map[string]bool)
+	for _, name := range gatewayIDHeaders() {
+		ids[strings.ToLower(name)] = true
+	}
+	tracing := make(map[string]bool)
+	for _, name := range reqctx.TracingHeaderNames() {
+		tracing[strings.ToLower(name)] = true
+	}
+
+	return func(key string) (string, bool) {
+		lower := strings.ToLower(key)
+		if ids[lower] {
+			return lower, true
+		}
+		if tracing[lower] || tracing[strings.TrimPrefix(lower, gatewayMetadataPrefix)] {
+			return "", false
+		}
+		if next == nil {
+			return "", false
+		}
+		return next(key)
+	}
+}
+
+// GatewayTracingMetadata forwards the other tracing headers of trusted grpc-gateway requests as gRPC metadata
+// The result is a runtime.WithMetadata annotator for the headers GatewayHeaderMatcher drops. They are
+// forwarded only if cfg.TrustedProxies is set and the request comes through it (see httputil.TrustCheck),
+// X-Dry-Run only with cfg.HonorDryRun too. The backend must trust the gateway as well, see
+// RequestIDUnaryServerInterceptorWithConfig.
+//
+// Usage:
+//
+//	mux := runtime.NewServeMux(
+//		runtime.WithIncomingHeaderMatcher(grpcutil.GatewayHeaderMatcher(runtime.DefaultHeaderMatcher)),
+//		runtime.WithMetadata(grpcutil.GatewayTracingMetadata(httputil.Config{
+//			TrustedProxies: &httputil.TrustedProxyConfig{CIDRs: []string{"10.0.0.0/8"}},
+//		})),
+//	)
+func GatewayTracingMetadata(cfg httputil.Config) func(ctx context.Context, r *http.Request) metadata.MD {
+	trusted := httputil.TrustCheck(cfg)
+	ids := make(map[string]bool)
+	for _, name := range gatewayIDHeaders() {
+		ids[name] = true
+	}
+	var names []string
+	for _, name := range reqctx.TracingHeaderNames() {
+		if ids[name] || (name == reqctx.HeaderDryRun && !cfg.HonorDryRun) {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	return func(_ context.Context, r *http.Request) metadata.MD {
+		if cfg.TrustedProxies == nil || !trusted(r) {
+			return nil
+		}
+		md := metadata.MD{}
+		for _, name := range names {
+			if value := r.Header.Get(name); value != "" {
+				md.Set(strings.ToLower(name), value)
+			}
+		}
+		return md
+	}
+}
+
+// gatewayIDHeaders are the headers GatewayHeaderMatcher forwards from every caller
+func gatewayIDHeaders() []string {
+	return []string{reqctx.HeaderRequestID, reqctx.HeaderCorrelationID, reqctx.HeaderParentRequestID}
+}
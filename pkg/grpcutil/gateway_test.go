@@ -0,0 +1,109 @@
+package grpcutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+)
+
+func TestGatewayHeaderMatcher(t *testing.T) {
+	next := func(key string) (string, bool) {
+		// like runtime.DefaultHeaderMatcher for Grpc-Metadata- headers
+		if strings.HasPrefix(strings.ToLower(key), gatewayMetadataPrefix) {
+			return key[len(gatewayMetadataPrefix):], true
+		}
+		return "", false
+	}
+	matcher := GatewayHeaderMatcher(next)
+
+	tests := []struct {
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{key: "X-Request-Id", want: "x-request-id", wantOK: true},
+		{key: "X-Correlation-Id", want: "x-correlation-id", wantOK: true},
+		{key: "X-Parent-Request-Id", want: "x-parent-request-id", wantOK: true},
+		{key: "X-Dry-Run"},
+		{key: "X-Risk-Score"},
+		{key: "X-Request-Priority"},
+		{key: "X-Trace-Sampled"},
+		{key: "X-Client-Ip"},
+		{key: "X-Tenant-Id"},
+		{key: "Idempotency-Key"},
+		{key: "Baggage"},
+		{key: "Grpc-Metadata-X-Dry-Run"},
+		{key: "Grpc-Metadata-X-Risk-Score"},
+		{key: "Grpc-Metadata-Custom", want: "Custom", wantOK: true},
+		{key: "Authorization"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, ok := matcher(tt.key)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("matcher(%q) = %q, %v, want %q, %v", tt.key, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if _, ok := GatewayHeaderMatcher(nil)("Authorization"); ok {
+		t.Error("nil next forwarded Authorization")
+	}
+}
+
+func TestGatewayTracingMetadata(t *testing.T) {
+	proxies := &httputil.TrustedProxyConfig{CIDRs: []string{"10.0.0.0/8"}}
+
+	tests := []struct {
+		name       string
+		cfg        httputil.Config
+		remoteAddr string
+		want       metadata.MD
+	}{
+		{name: "no trusted proxies", remoteAddr: "10.1.2.3:4567"},
+		{name: "untrusted peer", cfg: httputil.Config{TrustedProxies: proxies}, remoteAddr: "203.0.113.7:4567"},
+		{
+			name:       "AlwaysRegenerate",
+			cfg:        httputil.Config{TrustedProxies: proxies, TrustMode: httputil.AlwaysRegenerate},
+			remoteAddr: "10.1.2.3:4567",
+		},
+		{
+			name:       "trusted proxy",
+			cfg:        httputil.Config{TrustedProxies: proxies},
+			remoteAddr: "10.1.2.3:4567",
+			want:       metadata.Pairs("x-request-priority", "high", "x-risk-score", "0.2"),
+		},
+		{
+			name:       "trusted proxy with HonorDryRun",
+			cfg:        httputil.Config{TrustedProxies: proxies, HonorDryRun: true},
+			remoteAddr: "10.1.2.3:4567",
+			want:       metadata.Pairs("x-request-priority", "high", "x-risk-score", "0.2", "x-dry-run", "true"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Request-ID", "req-1")
+			req.Header.Set("X-Request-Priority", "high")
+			req.Header.Set("X-Risk-Score", "0.2")
+			req.Header.Set("X-Dry-Run", "true")
+
+			got := GatewayTracingMetadata(tt.cfg)(context.Background(), req)
+			if len(got) != len(tt.want) {
+				t.Fatalf("metadata = %v, want %v", got, tt.want)
+			}
+			for key, values := range tt.want {
+				if g := got.Get(key); len(g) != 1 || g[0] != values[0] {
+					t.Errorf("%s = %q, want %q", key, g, values)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package grpcutil
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// ServerConfig configures RequestIDUnaryServerInterceptorWithConfig
+type ServerConfig struct {
+	// Trusted reports whether the peer of ctx may set the values gated by reqctx.ExtractOptions,
+	// e.g. by checking peer.FromContext or the client certificate. Nil trusts no peer.
+	Trusted func(ctx context.Context) bool
+
+	// HonorDryRun reads x-dry-run metadata of trusted peers, see reqctx.ExtractOptions.HonorDryRun
+	HonorDryRun bool
+}
+
+// extractOptions returns the reqctx.ExtractOptions for the peer of ctx
+func (cfg ServerConfig) extractOptions(ctx context.Context) reqctx.ExtractOptions {
+	return reqctx.ExtractOptions{
+		Trusted:     cfg.Trusted != nil && cfg.Trusted(ctx),
+		HonorDryRun: cfg.HonorDryRun,
+	}
+}
+
+// RequestIDUnaryServerInterceptor populates the handler context with the tracing identifiers of incoming metadata
+// They are read like httputil reads HTTP headers (see reqctx.ExtractContext): the request ID is generated
+// if absent and the correlation ID defaults to the request ID. Both are sent back as response header metadata,
+// so handlers can use httputil.GetRequestIDFromContext (or reqctx) exactly like HTTP handlers.
+// No peer is trusted, see RequestIDUnaryServerInterceptorWithConfig.
+//
+// Usage:
+//
+//	server := grpc.NewServer(
+//		grpc.UnaryInterceptor(grpcutil.RequestIDUnaryServerInterceptor()),
+//	)
+func RequestIDUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return RequestIDUnaryServerInterceptorWithConfig(ServerConfig{})
+}
+
+// RequestIDUnaryServerInterceptorWithConfig is RequestIDUnaryServerInterceptor honoring sampling, priority,
+// client IP and risk score metadata of the peers cfg.Trusted accepts, like httputil does for Config.TrustedProxies,
+// and their dry-run flag with cfg.HonorDryRun
+//
+// Usage:
+//
+//	server := grpc.NewServer(
+//		grpc.UnaryInterceptor(grpcutil.RequestIDUnaryServerInterceptorWithConfig(grpcutil.ServerConfig{
+//			Trusted: func(ctx context.Context) bool {
+//				p, ok := peer.FromContext(ctx)
+//				return ok && gatewayNet.Contains(p.Addr.(*net.TCPAddr).IP)
+//			},
+//		})),
+//	)
+func RequestIDUnaryServerInterceptorWithConfig(cfg ServerConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		ctx = reqctx.ExtractContextWithOptions(ctx, MetadataCarrier(md), cfg.extractOptions(ctx))
+		requestID := reqctx.GetRequestIDFromContext(ctx)
+		correlationID := reqctx.GetCorrelationIDFromContext(ctx)
+
+		// SetHeader fails only if headers were already sent, which can't happen before the handler runs
+		_ = grpc.SetHeader(ctx, metadata.Pairs(
+			MetadataRequestID, requestID,
+			MetadataCorrelationID, correlationID,
+		))
+
+		return handler(ctx, req)
+	}
+}
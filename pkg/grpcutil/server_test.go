@@ -0,0 +1,98 @@
+package grpcutil
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// runServerInterceptor calls interceptor with md as incoming metadata and returns the handler context
+func runServerInterceptor(t *testing.T, interceptor grpc.UnaryServerInterceptor, md metadata.MD) context.Context {
+	t.Helper()
+
+	var got context.Context
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		got = ctx
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestRequestIDUnaryServerInterceptorTrust(t *testing.T) {
+	md := metadata.Pairs(
+		MetadataRequestID, "req-1",
+		"x-trace-sampled", "0",
+		"x-request-priority", "high",
+		"x-client-ip", "203.0.113.7",
+		"x-risk-score", "0",
+	)
+
+	for _, tt := range []struct {
+		name        string
+		interceptor grpc.UnaryServerInterceptor
+		wantTrusted bool
+	}{
+		{name: "default", interceptor: RequestIDUnaryServerInterceptor()},
+		{name: "nil Trusted", interceptor: RequestIDUnaryServerInterceptorWithConfig(ServerConfig{})},
+		{
+			name:        "trusted peer",
+			interceptor: RequestIDUnaryServerInterceptorWithConfig(ServerConfig{Trusted: func(context.Context) bool { return true }}),
+			wantTrusted: true,
+		},
+		{
+			name:        "untrusted peer",
+			interceptor: RequestIDUnaryServerInterceptorWithConfig(ServerConfig{Trusted: func(context.Context) bool { return false }}),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := runServerInterceptor(t, tt.interceptor, md)
+
+			if id, _ := reqctx.RequestIDFromContext(ctx); id != "req-1" {
+				t.Errorf("request ID = %q, want req-1", id)
+			}
+			if honored := !reqctx.IsSampled(ctx); honored != tt.wantTrusted {
+				t.Errorf("sampling decision honored = %v, want %v", honored, tt.wantTrusted)
+			}
+			if honored := reqctx.PriorityFromContext(ctx) == reqctx.PriorityHigh; honored != tt.wantTrusted {
+				t.Errorf("priority honored = %v, want %v", honored, tt.wantTrusted)
+			}
+			if _, honored := reqctx.ClientIPFromContext(ctx); honored != tt.wantTrusted {
+				t.Errorf("client IP honored = %v, want %v", honored, tt.wantTrusted)
+			}
+			if _, honored := reqctx.RiskScoreFromContext(ctx); honored != tt.wantTrusted {
+				t.Errorf("risk score honored = %v, want %v", honored, tt.wantTrusted)
+			}
+		})
+	}
+}
+
+func TestRequestIDUnaryServerInterceptorDryRun(t *testing.T) {
+	md := metadata.Pairs("x-dry-run", "true")
+	trusted := func(context.Context) bool { return true }
+
+	for _, tt := range []struct {
+		name string
+		cfg  ServerConfig
+		want bool
+	}{
+		{name: "default", want: false},
+		{name: "HonorDryRun without trust", cfg: ServerConfig{HonorDryRun: true}, want: false},
+		{name: "trusted without HonorDryRun", cfg: ServerConfig{Trusted: trusted}, want: false},
+		{name: "trusted with HonorDryRun", cfg: ServerConfig{Trusted: trusted, HonorDryRun: true}, want: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := runServerInterceptor(t, RequestIDUnaryServerInterceptorWithConfig(tt.cfg), md)
+			if got := reqctx.DryRunFromContext(ctx); got != tt.want {
+				t.Errorf("dry run = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package httputil
+
+import "strings"
+
+// HeaderAmznTraceID is the trace header injected by AWS load balancers
+const HeaderAmznTraceID = "X-Amzn-Trace-Id"
+
+// amznTraceRoot returns the Root= segment of an X-Amzn-Trace-Id header, e.g. "1-5759e988-bd862e3fe1be46a994272793"
+// Header format: "Self=1-...;Root=1-5759e988-bd862e3fe1be46a994272793;Sampled=1", fields in any order
+func amznTraceRoot(header string) string {
+	for _, field := range strings.Split(header, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if ok && strings.EqualFold(key, "Root") {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
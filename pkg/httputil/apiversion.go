@@ -0,0 +1,80 @@
+package httputil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderAPIVersion carries the API contract version the call chain must honor
+// APIVersionMiddleware reads and validates it, outgoing requests forward it.
+const HeaderAPIVersion = reqctx.HeaderAPIVersion
+
+// ContextWithAPIVersion creates a new context with the API version
+func ContextWithAPIVersion(ctx context.Context, version string) context.Context {
+	return reqctx.ContextWithAPIVersion(ctx, version)
+}
+
+// APIVersionFromContext returns the API version stored by APIVersionMiddleware and whether it was found
+// A *gin.Context is accepted too.
+func APIVersionFromContext(ctx context.Context) (string, bool) {
+	return reqctx.APIVersionFromContext(valueContext(ctx))
+}
+
+// APIVersionConfig configures APIVersionMiddleware
+type APIVersionConfig struct {
+	// Supported lists the accepted X-API-Version values, oldest first
+	Supported []string
+
+	// Default is the version of requests without X-API-Version, the last of Supported if empty
+	Default string
+}
+
+// APIVersionMiddleware validates X-API-Version against cfg.Supported and stores it in the request context
+// Unknown versions are rejected with 400, requests without the header get cfg.Default. The version
+// is forwarded on outgoing requests, so downstream services honor the same contract. Mount it after
+// RequestIDMiddleware. It panics at startup if Supported is empty or Default is not supported.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.APIVersionMiddleware(httputil.APIVersionConfig{
+//		Supported: []string{"2023-06-01", "2024-01-01"},
+//	}))
+//
+// Response:
+//
+//	{"error": {"code": "unsupported_api_version", "message": "Unsupported API version ..."}, "request_id": "..."}
+func APIVersionMiddleware(cfg APIVersionConfig) gin.HandlerFunc {
+	if len(cfg.Supported) == 0 {
+		panic("httputil: APIVersionConfig.Supported is empty")
+	}
+	supported := make(map[string]bool, len(cfg.Supported))
+	for _, version := range cfg.Supported {
+		supported[version] = true
+	}
+	if cfg.Default == "" {
+		cfg.Default = cfg.Supported[len(cfg.Supported)-1]
+	}
+	if !supported[cfg.Default] {
+		panic("httputil: default API version " + cfg.Default + " is not supported")
+	}
+	message := fmt.Sprintf("Unsupported API version, supported: %s", strings.Join(cfg.Supported, ", "))
+
+	return func(c *gin.Context) {
+		version := strings.TrimSpace(c.GetHeader(HeaderAPIVersion))
+		if version == "" {
+			version = cfg.Default
+		}
+		if !supported[version] {
+			RespondError(c, http.StatusBadRequest, "unsupported_api_version", message)
+			return
+		}
+		c.Request = c.Request.WithContext(ContextWithAPIVersion(c.Request.Context(), version))
+		c.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderBaggage is the W3C baggage header
+const HeaderBaggage = reqctx.HeaderBaggage
+
+// MaxBaggageSize is the maximum size in bytes of the encoded Baggage header
+// Members that don't fit are dropped on propagation, larger incoming headers are ignored
+const MaxBaggageSize = reqctx.MaxBaggageSize
+
+// ContextWithBaggage creates a new context with an additional baggage member
+// Baggage carries small key/value pairs (feature-flag overrides, experiment bucket) across hops.
+// Keys must be valid HTTP tokens, invalid keys are ignored.
+//
+// Usage:
+//
+//	ctx = httputil.ContextWithBaggage(ctx, "experiment", "checkout-v2")
+func ContextWithBaggage(ctx context.Context, key, value string) context.Context {
+	return reqctx.ContextWithBaggage(coreContext(ctx), key, value)
+}
+
+// BaggageFromContext returns a copy of the baggage members stored in ctx
+func BaggageFromContext(ctx context.Context) map[string]string {
+	return reqctx.BaggageFromContext(valueContext(ctx))
+}
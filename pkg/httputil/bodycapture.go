@@ -0,0 +1,127 @@
+package httputil
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultBodyCaptureBytes is the default per-body capture cap of BodyCaptureMiddleware
+const DefaultBodyCaptureBytes = 64 << 10
+
+// BodyCaptureSink receives the captured bodies of a request, truncated to the capture cap
+type BodyCaptureSink func(requestID string, req, resp []byte)
+
+// BodyCaptureOptions configures BodyCaptureMiddleware
+type BodyCaptureOptions struct {
+	// Sink receives the captured bodies after the handler returns, nothing is captured if nil
+	Sink BodyCaptureSink
+
+	// MaxBytes caps each captured body, DefaultBodyCaptureBytes is used if <= 0
+	// Bodies are still passed through in full, only the captured copy is truncated.
+	MaxBytes int64
+
+	// Routes limits capturing to these gin route templates, e.g. "/webhooks/:provider"
+	// Empty captures every route the middleware is mounted on.
+	Routes []string
+}
+
+// BodyCaptureMiddleware captures request and response bodies tagged with the request_id for debugging
+// The request body is buffered up to MaxBytes and put back into c.Request.Body, so the handler reads
+// it in full; the response is teed while written. Sink is called synchronously after the handler,
+// hand the bodies to a goroutine if storing them is slow. Bodies may contain secrets, enable it
+// only for the routes being debugged.
+//
+// Usage:
+//
+//	router.POST("/webhooks/:provider", httputil.BodyCaptureMiddleware(httputil.BodyCaptureOptions{
+//		MaxBytes: 16 << 10,
+//		Sink: func(requestID string, req, resp []byte) {
+//			logger.Debug("webhook bodies", "request_id", requestID, "req", string(req), "resp", string(resp))
+//		},
+//	}), h.Webhook)
+func BodyCaptureMiddleware(opts BodyCaptureOptions) gin.HandlerFunc {
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultBodyCaptureBytes
+	}
+	routes := make(map[string]bool, len(opts.Routes))
+	for _, route := range opts.Routes {
+		routes[route] = true
+	}
+
+	return func(c *gin.Context) {
+		if opts.Sink == nil || (len(routes) > 0 && !routes[c.FullPath()]) {
+			c.Next()
+			return
+		}
+
+		var reqBody []byte
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			var err error
+			reqBody, err = io.ReadAll(io.LimitReader(c.Request.Body, maxBytes))
+			// the captured prefix is replayed before the unread rest, read errors surface to the handler
+			c.Request.Body = &replayBody{
+				Reader: io.MultiReader(bytes.NewReader(reqBody), errReader{err}, c.Request.Body),
+				Closer: c.Request.Body,
+			}
+		}
+
+		w := &captureWriter{ResponseWriter: c.Writer, limit: maxBytes}
+		c.Writer = w
+		c.Next()
+
+		opts.Sink(GetRequestID(c), reqBody, w.buf.Bytes())
+	}
+}
+
+// replayBody reads the captured prefix and then the rest of the original body, Close closes the original
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// errReader returns err once it is reached, io.EOF if err is nil
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
+
+// captureWriter keeps a copy of the first limit bytes of the response body
+type captureWriter struct {
+	gin.ResponseWriter
+	buf   bytes.Buffer
+	limit int64
+}
+
+// Write implements http.ResponseWriter
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString implements gin.ResponseWriter
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *captureWriter) capture(b []byte) {
+	if room := w.limit - int64(w.buf.Len()); room > 0 {
+		if int64(len(b)) > room {
+			b = b[:room]
+		}
+		w.buf.Write(b)
+	}
+}
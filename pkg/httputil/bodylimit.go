@@ -0,0 +1,142 @@
+package httputil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimitOption configures MaxBodyBytes
+type BodyLimitOption func(*bodyLimitConfig)
+
+type bodyLimitConfig struct {
+	routeLimits map[string]int64
+	readTimeout time.Duration
+}
+
+// ErrBodyReadTimeout is returned by request body reads after the WithReadTimeout deadline passed
+var ErrBodyReadTimeout = errors.New("httputil: request body read timeout")
+
+// WithRouteLimit overrides the body limit for a gin route template, e.g. "/uploads/:id"
+func WithRouteLimit(route string, limit int64) BodyLimitOption {
+	return func(cfg *bodyLimitConfig) {
+		cfg.routeLimits[route] = limit
+	}
+}
+
+// WithReadTimeout limits the time to read the whole request body, counted from the handler chain start
+// Protects handlers from slowloris-style clients trickling a body. The connection read deadline
+// is set, so a blocked read is interrupted too, and body reads past the deadline fail with
+// ErrBodyReadTimeout. If the handler wrote nothing, 408 is sent after it returns.
+func WithReadTimeout(timeout time.Duration) BodyLimitOption {
+	return func(cfg *bodyLimitConfig) {
+		cfg.readTimeout = timeout
+	}
+}
+
+// MaxBodyBytes limits request body size to limit bytes
+// Requests with a larger Content-Length are rejected with 413 before the handler runs.
+// Otherwise the body is wrapped with http.MaxBytesReader, so streaming and buffered readers
+// fail once the limit is crossed; if the handler wrote nothing, 413 is sent after it returns.
+// Handlers can detect the case with errors.As(err, new(*http.MaxBytesError)).
+// All 413 responses are RespondError bodies carrying the request_id, as are 408 responses of WithReadTimeout.
+//
+// Usage:
+//
+//	router.Use(httputil.MaxBodyBytes(1<<20,
+//		httputil.WithRouteLimit("/uploads", 100<<20),
+//		httputil.WithReadTimeout(30*time.Second),
+//	))
+func MaxBodyBytes(limit int64, opts ...BodyLimitOption) gin.HandlerFunc {
+	cfg := bodyLimitConfig{routeLimits: make(map[string]int64)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(c *gin.Context) {
+		routeLimit := limit
+		if l, ok := cfg.routeLimits[c.FullPath()]; ok {
+			routeLimit = l
+		}
+
+		if c.Request.ContentLength > routeLimit {
+			respondBodyTooLarge(c, routeLimit)
+			return
+		}
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		body := &limitedBody{ReadCloser: http.MaxBytesReader(c.Writer, c.Request.Body, routeLimit)}
+		c.Request.Body = body
+
+		var timed *timedBody
+		if cfg.readTimeout > 0 {
+			deadline := time.Now().Add(cfg.readTimeout)
+			rc := http.NewResponseController(c.Writer)
+			// unsupported writers only get the per-read deadline check of timedBody
+			if rc.SetReadDeadline(deadline) == nil {
+				defer func() { _ = rc.SetReadDeadline(time.Time{}) }()
+			}
+			timed = &timedBody{ReadCloser: body, deadline: deadline}
+			c.Request.Body = timed
+		}
+		c.Next()
+
+		if c.Writer.Written() {
+			return
+		}
+		switch {
+		case body.exceeded:
+			respondBodyTooLarge(c, routeLimit)
+		case timed != nil && timed.timedOut:
+			RespondError(c, http.StatusRequestTimeout, "request_timeout", "Request body was not received in time")
+		}
+	}
+}
+
+// timedBody fails reads once deadline has passed
+type timedBody struct {
+	io.ReadCloser
+	deadline time.Time
+	timedOut bool
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	if !time.Now().Before(b.deadline) {
+		b.timedOut = true
+		return 0, ErrBodyReadTimeout
+	}
+	n, err := b.ReadCloser.Read(p)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		b.timedOut = true
+		err = fmt.Errorf("%w: %w", ErrBodyReadTimeout, err)
+	}
+	return n, err
+}
+
+// limitedBody remembers whether the wrapped http.MaxBytesReader hit its limit
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+func respondBodyTooLarge(c *gin.Context, limit int64) {
+	RespondError(c, http.StatusRequestEntityTooLarge, "request_too_large",
+		fmt.Sprintf("Request body exceeds %d bytes", limit))
+}
@@ -0,0 +1,51 @@
+package httputil
+
+import "net/http"
+
+// HandlerChain is an immutable list of net/http middlewares, see Chain
+type HandlerChain struct {
+	middlewares []func(http.Handler) http.Handler
+}
+
+// Chain composes net/http middlewares, the net/http counterpart of router.Use
+// The first listed is the outermost: Chain(a, b).Then(h) is a(b(h)), so a request passes
+// a, then b, then h, same as router.Use(a, b) in gin. Put RequestIDHandler first so
+// everything below it sees the request_id.
+//
+// Usage:
+//
+//	handler := httputil.Chain(
+//		httputil.RequestIDHandler,
+//		gzipHandler,
+//	).Then(mux)
+//	http.ListenAndServe(":8080", handler)
+func Chain(middlewares ...func(http.Handler) http.Handler) HandlerChain {
+	return HandlerChain{middlewares: append([]func(http.Handler) http.Handler(nil), middlewares...)}
+}
+
+// Append returns a new chain with middlewares added after (inside) the existing ones
+// The receiver is not modified, so a base chain can be shared between routes.
+func (ch HandlerChain) Append(middlewares ...func(http.Handler) http.Handler) HandlerChain {
+	combined := make([]func(http.Handler) http.Handler, 0, len(ch.middlewares)+len(middlewares))
+	combined = append(combined, ch.middlewares...)
+	return HandlerChain{middlewares: append(combined, middlewares...)}
+}
+
+// Then wraps final with the chain, http.DefaultServeMux is used if final is nil
+func (ch HandlerChain) Then(final http.Handler) http.Handler {
+	if final == nil {
+		final = http.DefaultServeMux
+	}
+	for i := len(ch.middlewares) - 1; i >= 0; i-- {
+		final = ch.middlewares[i](final)
+	}
+	return final
+}
+
+// ThenFunc is Then for a handler function
+func (ch HandlerChain) ThenFunc(final http.HandlerFunc) http.Handler {
+	if final == nil {
+		return ch.Then(nil)
+	}
+	return ch.Then(final)
+}
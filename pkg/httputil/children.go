@@ -0,0 +1,32 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// MaxChildRequestIDs bounds the child request IDs collected per request
+const MaxChildRequestIDs = reqctx.MaxChildRequestIDs
+
+// ContextWithChildCollector makes NewChildRequestID and PropagatingTransport record downstream
+// request IDs in ctx. The request ID middlewares install it with Config.CollectChildRequestIDs.
+func ContextWithChildCollector(ctx context.Context) context.Context {
+	return reqctx.ContextWithChildCollector(ctx)
+}
+
+// ChildRequestIDsFromContext returns the request IDs of downstream calls made so far, in call order
+// A *gin.Context is accepted too. At most MaxChildRequestIDs are kept, see DroppedChildRequestIDs.
+func ChildRequestIDsFromContext(ctx context.Context) []string {
+	return reqctx.ChildRequestIDsFromContext(valueContext(ctx))
+}
+
+// DroppedChildRequestIDs returns how many child request IDs were not kept because of MaxChildRequestIDs
+func DroppedChildRequestIDs(ctx context.Context) int {
+	return reqctx.DroppedChildRequestIDs(valueContext(ctx))
+}
+
+// RecordChildRequestID adds id to the collector of ctx, a no-op without ContextWithChildCollector
+func RecordChildRequestID(ctx context.Context, id string) {
+	reqctx.RecordChildRequestID(valueContext(ctx), id)
+}
@@ -0,0 +1,262 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is matched by errors.Is for requests shed by CircuitBreakerTransport
+var ErrCircuitOpen = errors.New("httputil: circuit open")
+
+// CircuitState is the state of the circuit breaker of one host
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through and counts failures
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen sheds every request until CircuitBreakerOptions.OpenTimeout passes
+	CircuitOpen
+
+	// CircuitHalfOpen lets a single probe request through, its outcome closes or reopens the circuit
+	CircuitHalfOpen
+)
+
+// String returns "closed", "open" or "half_open", suitable as a metric label
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half_open"
+	}
+	return "closed"
+}
+
+// CircuitOpenError is returned for a request shed by an open circuit, it matches ErrCircuitOpen
+// RequestID is the request ID of the request context, or of the X-Request-ID header if
+// the context has none, so shed requests stay traceable in logs.
+type CircuitOpenError struct {
+	Host      string
+	RequestID string
+}
+
+// Error implements error, the message includes the host and request_id
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("httputil: circuit open for %s (request_id=%s)", e.Host, e.RequestID)
+}
+
+// Is reports whether target is ErrCircuitOpen
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitBreakerOptions configures NewCircuitBreakerTransport
+type CircuitBreakerOptions struct {
+	// FailureRate opens the circuit when reached by the failed share of requests in a window, defaults to 0.5
+	FailureRate float64
+
+	// MinRequests is the number of requests in a window before FailureRate is checked, defaults to 20
+	MinRequests int
+
+	// Window is the period requests and failures are counted over, defaults to 10s
+	Window time.Duration
+
+	// OpenTimeout is how long an open circuit sheds requests before letting a probe through, defaults to 30s
+	OpenTimeout time.Duration
+
+	// IsFailure decides whether a request counts as failed, defaults to DefaultCircuitFailure
+	IsFailure func(resp *http.Response, err error) bool
+
+	// OnStateChange is called after the circuit of host changed state, e.g. to update a metric
+	// It is called with the breaker lock held, it must not call back into the transport.
+	OnStateChange func(host string, from, to CircuitState)
+}
+
+// DefaultCircuitFailure counts transport errors and 5xx responses as failures
+// Requests cancelled by their caller say nothing about the host and are not counted.
+func DefaultCircuitFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// CircuitBreakerTransport is an http.RoundTripper with a failure-rate based circuit breaker per host
+// Requests to a host with an open circuit fail fast with a *CircuitOpenError carrying their request_id.
+// It is safe for concurrent use.
+type CircuitBreakerTransport struct {
+	base http.RoundTripper
+	opts CircuitBreakerOptions
+
+	mu       sync.Mutex
+	breakers map[string]*hostBreaker
+}
+
+// hostBreaker is the breaker state of one host
+type hostBreaker struct {
+	state       CircuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probing     bool
+}
+
+// NewCircuitBreakerTransport wraps base with a circuit breaker per request host
+// Any RoundTripper works as base, http.DefaultTransport is used if nil. Layer it over a
+// PropagatingTransport so shed requests never reach the network, and under retries so a retry
+// doesn't hammer an open circuit (DefaultRetryable doesn't retry ErrCircuitOpen).
+//
+// Usage:
+//
+//	breaker := httputil.NewCircuitBreakerTransport(httputil.NewPropagatingTransport(nil), httputil.CircuitBreakerOptions{})
+//	client := &http.Client{Transport: breaker}
+//
+//	resp, err := client.Do(req)
+//	var open *httputil.CircuitOpenError
+//	if errors.As(err, &open) {
+//		logger.Warn("request shed", "host", open.Host, "request_id", open.RequestID)
+//	}
+func NewCircuitBreakerTransport(base http.RoundTripper, opts CircuitBreakerOptions) *CircuitBreakerTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if opts.FailureRate <= 0 {
+		opts.FailureRate = 0.5
+	}
+	if opts.MinRequests <= 0 {
+		opts.MinRequests = 20
+	}
+	if opts.Window <= 0 {
+		opts.Window = 10 * time.Second
+	}
+	if opts.OpenTimeout <= 0 {
+		opts.OpenTimeout = 30 * time.Second
+	}
+	if opts.IsFailure == nil {
+		opts.IsFailure = DefaultCircuitFailure
+	}
+	return &CircuitBreakerTransport{base: base, opts: opts, breakers: make(map[string]*hostBreaker)}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *CircuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	probe, ok := t.allow(host, time.Now())
+	if !ok {
+		requestID, _ := RequestIDFromContext(req.Context())
+		if requestID == "" {
+			requestID = req.Header.Get(HeaderRequestID)
+		}
+		return nil, &CircuitOpenError{Host: host, RequestID: requestID}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	t.record(host, probe, t.opts.IsFailure(resp, err), time.Now())
+	return resp, err
+}
+
+// State returns the circuit state of host, CircuitClosed for hosts not seen yet
+func (t *CircuitBreakerTransport) State(host string) CircuitState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if b, ok := t.breakers[host]; ok {
+		return b.state
+	}
+	return CircuitClosed
+}
+
+// States returns the circuit state of every host seen so far, e.g. for a gauge per host
+//
+// Usage:
+//
+//	for host, state := range breaker.States() {
+//		circuitState.WithLabelValues(host).Set(float64(state))
+//	}
+func (t *CircuitBreakerTransport) States() map[string]CircuitState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	states := make(map[string]CircuitState, len(t.breakers))
+	for host, b := range t.breakers {
+		states[host] = b.state
+	}
+	return states
+}
+
+// allow reports whether a request to host may be sent and whether it is the half-open probe
+func (t *CircuitBreakerTransport) allow(host string, now time.Time) (probe, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, found := t.breakers[host]
+	if !found {
+		b = &hostBreaker{windowStart: now}
+		t.breakers[host] = b
+	}
+
+	switch b.state {
+	case CircuitOpen:
+		if now.Sub(b.openedAt) < t.opts.OpenTimeout {
+			return false, false
+		}
+		t.setState(host, b, CircuitHalfOpen)
+		b.probing = true
+		return true, true
+	case CircuitHalfOpen:
+		if b.probing {
+			return false, false
+		}
+		b.probing = true
+		return true, true
+	}
+	return false, true
+}
+
+// record counts the outcome of a request to host, opening or closing the circuit as needed
+func (t *CircuitBreakerTransport) record(host string, probe, failed bool, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := t.breakers[host]
+	if probe {
+		b.probing = false
+		if failed {
+			b.openedAt = now
+			t.setState(host, b, CircuitOpen)
+			return
+		}
+		b.windowStart, b.requests, b.failures = now, 0, 0
+		t.setState(host, b, CircuitClosed)
+		return
+	}
+	if b.state != CircuitClosed {
+		// a request sent before the circuit opened, its outcome is already accounted for
+		return
+	}
+
+	if now.Sub(b.windowStart) > t.opts.Window {
+		b.windowStart, b.requests, b.failures = now, 0, 0
+	}
+	b.requests++
+	if failed {
+		b.failures++
+	}
+	if b.requests >= t.opts.MinRequests && float64(b.failures)/float64(b.requests) >= t.opts.FailureRate {
+		b.openedAt = now
+		t.setState(host, b, CircuitOpen)
+	}
+}
+
+// setState moves b to state and reports the change, t.mu must be held
+func (t *CircuitBreakerTransport) setState(host string, b *hostBreaker, state CircuitState) {
+	from := b.state
+	b.state = state
+	if t.opts.OnStateChange != nil && from != state {
+		t.opts.OnStateChange(host, from, state)
+	}
+}
@@ -0,0 +1,155 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ClientOption configures NewTracingClient
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	timeout             time.Duration
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+	retry               *RetryOptions
+	circuitBreaker      *CircuitBreakerOptions
+	formats             []Format
+	internalHosts       []string
+}
+
+// WithTimeout sets the total request timeout, 30s by default
+func WithTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = d
+	}
+}
+
+// WithMaxIdleConns sets the connection pool size and per-host limit, 100 and 10 by default
+func WithMaxIdleConns(total, perHost int) ClientOption {
+	return func(o *clientOptions) {
+		o.maxIdleConns = total
+		o.maxIdleConnsPerHost = perHost
+	}
+}
+
+// WithIdleConnTimeout sets how long idle pooled connections are kept, 90s by default
+func WithIdleConnTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.idleConnTimeout = d
+	}
+}
+
+// WithRetry retries requests as DoWithRetry does, every attempt gets its own request ID
+// Note that the client timeout covers all attempts together
+func WithRetry(opts RetryOptions) ClientOption {
+	return func(o *clientOptions) {
+		o.retry = &opts
+	}
+}
+
+// WithCircuitBreaker sheds requests to failing hosts as CircuitBreakerTransport does
+func WithCircuitBreaker(opts CircuitBreakerOptions) ClientOption {
+	return func(o *clientOptions) {
+		o.circuitBreaker = &opts
+	}
+}
+
+// WithInternalHosts sends all tracing headers to hosts matching internalHosts, see PolicyForHost
+// Without it every host is external and receives only the request ID.
+//
+// Usage:
+//
+//	client := httputil.NewTracingClient(httputil.WithInternalHosts("*.internal.example.com", "billing"))
+func WithInternalHosts(internalHosts ...string) ClientOption {
+	return func(o *clientOptions) {
+		o.internalHosts = internalHosts
+	}
+}
+
+// WithPropagationFormats sends the tracing headers in formats, e.g. to bridge to B3 or Datadog services
+// List NativeFormat too to keep sending the native headers. Formats encode what the propagation policy
+// lets through, so external hosts get trace IDs derived from the request ID alone.
+//
+// Usage:
+//
+//	zipkinClient := httputil.NewTracingClient(
+//		httputil.WithInternalHosts("*.zipkin.internal"),
+//		httputil.WithPropagationFormats(httputil.NativeFormat, httputil.B3MultiFormat),
+//	)
+func WithPropagationFormats(formats ...Format) ClientOption {
+	return func(o *clientOptions) {
+		o.formats = formats
+	}
+}
+
+// NewTracingClient returns a production-ready http.Client propagating tracing headers
+// Transport chain: retry (optional) -> circuit breaker (optional) -> PropagatingTransport -> pooled http.Transport.
+// Only hosts of WithInternalHosts receive all tracing headers, others get the request ID alone, retried
+// attempts included: retry hands the IDs of each attempt to PropagatingTransport in the request context.
+//
+// Usage:
+//
+//	client := httputil.NewTracingClient(
+//		httputil.WithInternalHosts("*.internal.example.com"),
+//		httputil.WithTimeout(10*time.Second),
+//		httputil.WithRetry(httputil.RetryOptions{MaxAttempts: 3}),
+//	)
+func NewTracingClient(opts ...ClientOption) *http.Client {
+	o := clientOptions{
+		timeout:             30 * time.Second,
+		maxIdleConns:        100,
+		maxIdleConnsPerHost: 10,
+		idleConnTimeout:     90 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = o.maxIdleConns
+	transport.MaxIdleConnsPerHost = o.maxIdleConnsPerHost
+	transport.IdleConnTimeout = o.idleConnTimeout
+
+	propagating := &PropagatingTransport{Base: transport, Formats: o.formats, InternalHosts: o.internalHosts}
+	var rt http.RoundTripper = propagating
+	if o.circuitBreaker != nil {
+		rt = NewCircuitBreakerTransport(rt, *o.circuitBreaker)
+	}
+	if o.retry != nil {
+		rt = &retryTransport{base: rt, opts: *o.retry, cfg: propagating.Config}
+	}
+
+	return &http.Client{
+		Transport: rt,
+		Timeout:   o.timeout,
+	}
+}
+
+// DoTraced sends req with the tracing headers of ctx, returning ctx.Err() without sending if ctx is done
+// The request is cloned with ctx, so req itself is not modified and client.Do observes the same
+// cancellation. Headers already set on req are replaced. http.DefaultClient is used if client is nil.
+// A *gin.Context is accepted as ctx too.
+//
+// Usage:
+//
+//	req, _ := http.NewRequest("GET", url, nil)
+//	resp, err := httputil.DoTraced(ctx, client, req)
+//	if errors.Is(err, context.Canceled) {
+//		return
+//	}
+func DoTraced(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	reqCtx := valueContext(ctx)
+	if err := reqCtx.Err(); err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req = req.Clone(reqCtx)
+	PropagateRequestIDFromContext(ctx, req)
+	return client.Do(req)
+}
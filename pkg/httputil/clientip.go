@@ -0,0 +1,79 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderClientIP carries the original client IP across internal hops
+// The request ID middlewares set it with Config.RecordClientIP, outgoing requests forward it.
+const HeaderClientIP = reqctx.HeaderClientIP
+
+// ContextWithClientIP creates a new context with the original client IP
+func ContextWithClientIP(ctx context.Context, ip string) context.Context {
+	return reqctx.ContextWithClientIP(ctx, ip)
+}
+
+// ClientIPFromContext returns the original client IP recorded with Config.RecordClientIP
+// A *gin.Context is accepted too.
+//
+// Usage:
+//
+//	ip, _ := httputil.ClientIPFromContext(c)
+//	logger.Warn("login failed", "client_ip", ip, "request_id", httputil.GetRequestID(c))
+func ClientIPFromContext(ctx context.Context) (string, bool) {
+	return reqctx.ClientIPFromContext(valueContext(ctx))
+}
+
+// resolveClientIP returns the original client IP of r, empty if it can't be determined
+// Forwarding headers are honored only from a trusted proxy, so without Config.TrustedProxies
+// the TCP peer address is used:
+//  1. X-Client-IP set by an internal service that already resolved it
+//  2. X-Forwarded-For, walked from the right skipping addresses of the trusted CIDRs,
+//     the first other address is the client
+//  3. the TCP peer address
+func resolveClientIP(r *http.Request, proxies *trustedProxies) string {
+	peer, ok := remoteAddr(r)
+	if proxies == nil || !proxies.trusts(r) {
+		if !ok {
+			return ""
+		}
+		return peer.String()
+	}
+
+	if ip, ok := reqctx.ParseClientIP(strings.TrimSpace(headerGet(r.Header, HeaderClientIP))); ok {
+		return ip
+	}
+
+	hops := forwardedFor(r.Header)
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !proxies.containsAddr(hops[i]) || i == 0 {
+			return hops[i].String()
+		}
+	}
+	if !ok {
+		return ""
+	}
+	return peer.String()
+}
+
+// forwardedFor returns the valid addresses of all X-Forwarded-For headers, leftmost first
+func forwardedFor(h http.Header) []netip.Addr {
+	var hops []netip.Addr
+	for _, value := range headerValues(h, "X-Forwarded-For") {
+		for _, hop := range strings.Split(value, ",") {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hop))
+			if err != nil {
+				// an unparsable hop breaks the chain, addresses left of it can't be verified
+				hops = hops[:0]
+				continue
+			}
+			hops = append(hops, addr.Unmap())
+		}
+	}
+	return hops
+}
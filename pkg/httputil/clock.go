@@ -0,0 +1,16 @@
+package httputil
+
+import "time"
+
+// Clock tells the current time, it lets tests control latency measured by middlewares
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock backed by time.Now, it is the default
+type SystemClock struct{}
+
+// Now returns time.Now()
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,36 @@
+package httputil
+
+import "net/http"
+
+// CloneTraced clones req for a hedged or speculative request with the same correlation ID and a new request ID
+// Headers are deep-copied, so editing the clone doesn't affect req. The correlation ID is taken from
+// req's X-Correlation-ID, then its X-Request-ID, then the tracing values of req.Context(), and set in
+// both, so all hedges share one trace while staying distinguishable in logs. If req.GetBody is set
+// the clone gets its own body, otherwise both share req.Body and only one of them may be sent.
+//
+// Usage:
+//
+//	hedge := httputil.CloneTraced(req)
+//	go func() { results <- send(hedge) }()
+func CloneTraced(req *http.Request) *http.Request {
+	correlationID := req.Header.Get(HeaderCorrelationID)
+	if correlationID == "" {
+		correlationID = req.Header.Get(HeaderRequestID)
+	}
+	if correlationID == "" {
+		correlationID = outgoingHeaders(req.Context(), DefaultConfig())[HeaderCorrelationID]
+	}
+
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			clone.Body = body
+		}
+	}
+	if clone.Header == nil {
+		clone.Header = make(http.Header)
+	}
+	clone.Header.Set(HeaderRequestID, NewRequestID())
+	clone.Header.Set(HeaderCorrelationID, correlationID)
+	return clone
+}
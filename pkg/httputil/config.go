@@ -0,0 +1,189 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// TrustMode controls whether incoming request IDs are honored
+type TrustMode int
+
+const (
+	// TrustIncoming honors valid incoming request IDs, it is the default
+	TrustIncoming TrustMode = iota
+
+	// AlwaysRegenerate ignores incoming request IDs and generates a fresh one for every request
+	// Intended for the public edge, where clients could reuse IDs to confuse logs.
+	// An incoming correlation ID is still honored.
+	AlwaysRegenerate
+)
+
+// Config configures request ID middlewares and transports
+// Zero values are replaced with defaults, so Config{} behaves like DefaultConfig()
+type Config struct {
+	// RequestIDHeader is the header the request ID is read from and written to
+	RequestIDHeader string
+
+	// CorrelationIDHeader is the header the correlation ID is read from and written to
+	CorrelationIDHeader string
+
+	// OnInvalidID is called when an incoming ID is rejected by ValidateRequestID and replaced
+	// err tells why the value was not trusted
+	OnInvalidID func(r *http.Request, id string, err error)
+
+	// RequireCorrelationID rejects requests without a valid incoming correlation ID with 400
+	// Intended for internal-only services behind the edge gateway. The rejection response
+	// still carries a generated request_id for debugging.
+	RequireCorrelationID bool
+
+	// ServicePrefix overrides SetServicePrefix for IDs generated by this middleware
+	ServicePrefix string
+
+	// IDGenerator overrides SetIDGenerator for IDs generated by this middleware
+	IDGenerator IDGenerator
+
+	// ExposeHeaders appends the request and correlation ID headers to Access-Control-Expose-Headers
+	// so browser clients can read them. Values set by an earlier CORS middleware are kept.
+	// With ResponsePolicy or ResponseHeaders only the echoed headers are exposed.
+	ExposeHeaders bool
+
+	// ResponsePolicy selects the identifiers echoed in response headers, PropagateInternal by default
+	// PropagateInternal writes the request ID, the correlation ID, X-Served-By and X-App-Version, PropagateExternal
+	// only the request ID, so a service facing external clients discloses no internal identifiers.
+	// Ignored if ResponseHeaders is set.
+	ResponsePolicy PropagationPolicy
+
+	// ResponseHeaders lists the headers echoed in responses explicitly, overriding ResponsePolicy
+	// Besides RequestIDHeader, CorrelationIDHeader, X-Served-By and X-App-Version it may name any header of
+	// reqctx.TracingHeaderNames, e.g. X-Tenant-ID, with the value of the request context. Headers
+	// without a value are not written. Meant for internal services, never list baggage for external ones.
+	ResponseHeaders []string
+
+	// UseAmznTraceID derives the request ID from the Root= segment of X-Amzn-Trace-Id
+	// when neither the request nor the correlation ID header is present.
+	// Lets logs be matched with AWS ALB access logs, enable it only behind an AWS load balancer.
+	UseAmznTraceID bool
+
+	// UseCloudTraceContext derives the request ID from the trace ID of X-Cloud-Trace-Context
+	// when neither the request nor the correlation ID header is present (after UseAmznTraceID).
+	// The parsed header is stored for CloudTraceFromContext and the Cloud Logging fields of
+	// SetCloudTraceProjectID. Enable it only behind Google Cloud load balancers or Cloud Run.
+	UseCloudTraceContext bool
+
+	// TrustMode controls whether incoming request IDs are honored, TrustIncoming by default
+	TrustMode TrustMode
+
+	// TrustedProxies, if set, honors incoming request IDs only from the reverse proxy it describes
+	// Requests reaching the service directly get a fresh request ID. An invalid CIDR panics when the
+	// middleware is created. Ignored with TrustMode AlwaysRegenerate.
+	TrustedProxies *TrustedProxyConfig
+
+	// SkipPaths lists request paths passed through untouched, without resolving or generating IDs
+	// A path ending with "*" is a prefix: "/debug/*" skips everything under /debug/
+	SkipPaths []string
+
+	// RequestIDKeyAlias additionally stores the request ID in gin.Context under this key
+	// For third-party middlewares reading a conventional key, e.g. GinContribRequestIDKey.
+	// SetRequestID keeps the alias in sync.
+	// Ignored by the net/http handler.
+	RequestIDKeyAlias string
+
+	// RecordStartTime stores the edge request start time from X-Request-Start in the request context,
+	// or the time the request was received if the header is missing. See StartTimeFromContext.
+	RecordStartTime bool
+
+	// SupportedLocales are the locales Accept-Language is matched against, see LocaleFromContext
+	// Empty uses the client's preferred tag as is. An X-Request-Locale set upstream is kept unmatched.
+	SupportedLocales []language.Tag
+
+	// DefaultLocale is the locale of requests without a usable Accept-Language or X-Request-Locale
+	// The zero value language.Und stores no locale for them.
+	DefaultLocale language.Tag
+
+	// RecordClientIP stores the original client IP in the request context and forwards it in X-Client-IP
+	// X-Client-IP and X-Forwarded-For are honored only from TrustedProxies, without it the TCP peer
+	// address is recorded. See ClientIPFromContext.
+	RecordClientIP bool
+
+	// CollectChildRequestIDs records the request IDs of downstream calls made while handling the request
+	// They are logged by AccessLogMiddleware as child_request_ids, see ChildRequestIDsFromContext.
+	CollectChildRequestIDs bool
+
+	// ServedBy is sent in the X-Served-By response header to tell which instance handled the request,
+	// e.g. the pod name. Empty sends nothing. Internal hostnames leak to clients, set it for internal
+	// services only or strip the header at the edge. Not written with ResponsePolicy PropagateExternal.
+	// See WithServedByHeader.
+	ServedBy string
+
+	// Version is sent in the X-App-Version response header and logged as app_version next to the
+	// request_id, so errors can be matched with a deploy during canary rollouts. Empty disables both.
+	// Not written with ResponsePolicy PropagateExternal unless listed in ResponseHeaders. See WithVersionHeader.
+	Version string
+
+	// HonorDryRun reads X-Dry-Run of trusted requests into the request context, see DryRunFromContext
+	// Off by default: a dry run skips side effects such as charges, so only a gateway that decides
+	// it may set the flag. Ignored with TrustMode AlwaysRegenerate and for peers outside TrustedProxies.
+	HonorDryRun bool
+
+	// MaxRequestDeadline caps the time budget honored from X-Request-Deadline, DefaultMaxRequestDeadline if zero
+	// See ApplyDeadlineFromHeaderWithConfig.
+	MaxRequestDeadline time.Duration
+
+	// ContextHooks are applied in order to the request context once IDs and other incoming values are stored
+	// Lets optional integrations add values without httputil depending on them, see otelutil.WithOtelBaggage.
+	ContextHooks []func(ctx context.Context) context.Context
+}
+
+// HeaderServedBy carries the name of the instance that handled the request, see Config.ServedBy
+const HeaderServedBy = "X-Served-By"
+
+// GinContribRequestIDKey is the gin.Context key conventionally used by gin-contrib/requestid users
+const GinContribRequestIDKey = "X-Request-ID"
+
+// DefaultConfig returns the configuration using X-Request-ID and X-Correlation-ID headers
+func DefaultConfig() Config {
+	return Config{
+		RequestIDHeader:     HeaderRequestID,
+		CorrelationIDHeader: HeaderCorrelationID,
+	}
+}
+
+// responseHeaders returns the headers the request ID middlewares echo, see Config.ResponseHeaders
+func (cfg Config) responseHeaders() []string {
+	switch {
+	case len(cfg.ResponseHeaders) > 0:
+		return cfg.ResponseHeaders
+	case cfg.ResponsePolicy == PropagateExternal:
+		return []string{cfg.RequestIDHeader}
+	}
+	return []string{cfg.RequestIDHeader, cfg.CorrelationIDHeader, HeaderServedBy, HeaderAppVersion}
+}
+
+// withDefaults fills empty fields with default values
+func (cfg Config) withDefaults() Config {
+	if cfg.RequestIDHeader == "" {
+		cfg.RequestIDHeader = HeaderRequestID
+	}
+	if cfg.CorrelationIDHeader == "" {
+		cfg.CorrelationIDHeader = HeaderCorrelationID
+	}
+	return cfg
+}
+
+// newRequestID generates a request ID honoring cfg.IDGenerator and cfg.ServicePrefix
+func (cfg Config) newRequestID() string {
+	gen := cfg.IDGenerator
+	if gen == nil {
+		gen = GetIDGenerator()
+	}
+	prefix := cfg.ServicePrefix
+	if prefix == "" {
+		prefix = reqctx.GetServicePrefix()
+	}
+	return reqctx.NewPrefixedID(gen, prefix)
+}
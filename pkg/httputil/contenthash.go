@@ -0,0 +1,54 @@
+package httputil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxContentHashBytes is the largest body ContentHashRequestID hashes
+const MaxContentHashBytes = 1 << 20
+
+// ContentHashRequestID replaces the request ID with a hex hash of the request body
+// Retries of the same payload, e.g. a redelivered webhook, then get the same request ID, which
+// makes it usable as a dedup key. hashFunc creates the hash, sha256.New is used if nil. The body
+// is buffered and put back into c.Request.Body, so the handler reads it in full. Bodies that are
+// empty, larger than MaxContentHashBytes or fail to read keep the request ID already assigned;
+// the correlation ID is never changed. Mount it on the route after RequestIDMiddleware, and not
+// together with ReplayGuardMiddleware, which would reject every retry.
+//
+// Usage:
+//
+//	router.POST("/webhooks/stripe", httputil.ContentHashRequestID(nil), h.StripeWebhook)
+func ContentHashRequestID(hashFunc func() hash.Hash) gin.HandlerFunc {
+	if hashFunc == nil {
+		hashFunc = sha256.New
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		// one byte over the cap tells an oversized body from one of exactly MaxContentHashBytes
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, MaxContentHashBytes+1))
+		c.Request.Body = &replayBody{
+			Reader: io.MultiReader(bytes.NewReader(body), errReader{err}, c.Request.Body),
+			Closer: c.Request.Body,
+		}
+
+		if err == nil && len(body) > 0 && len(body) <= MaxContentHashBytes {
+			h := hashFunc()
+			h.Write(body)
+			// a digest longer than the request ID limit fails validation and the ID is kept
+			_ = SetRequestID(c, hex.EncodeToString(h.Sum(nil)))
+		}
+		c.Next()
+	}
+}
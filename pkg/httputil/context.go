@@ -1,56 +1,231 @@
 // Package httputil provides HTTP utilities for request tracing and context propagation
+// The gin-free core lives in pkg/reqctx and is re-exported here, so values set through
+// either package are visible to both.
 package httputil
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
 )
 
 // contextKey is a custom type for context keys to avoid collisions
 type contextKey string
 
 const (
-	// requestIDKey is the context key for request ID
-	requestIDKey contextKey = "request_id"
-
 	// HeaderRequestID is the standard request ID header
-	HeaderRequestID = "X-Request-ID"
+	HeaderRequestID = reqctx.HeaderRequestID
 
 	// HeaderCorrelationID is the correlation ID header for distributed tracing
-	HeaderCorrelationID = "X-Correlation-ID"
+	HeaderCorrelationID = reqctx.HeaderCorrelationID
 )
 
 // RequestIDKey is the public string constant for gin.Context.Set/Get
 // Note: This is safe to use as string because gin.Context uses its own internal storage
 const RequestIDKey = "request_id"
 
+// CorrelationIDKey is the public string constant for gin.Context.Set/Get of the correlation ID
+const CorrelationIDKey = "correlation_id"
+
+// requestIDKeyAliasKey stores Config.RequestIDKeyAlias in gin.Context for setGinRequestID
+const requestIDKeyAliasKey = "httputil.request_id_key_alias"
+
+// setGinRequestID stores requestID under RequestIDKey and the Config.RequestIDKeyAlias the middleware recorded
+func setGinRequestID(c *gin.Context, requestID string) {
+	c.Set(RequestIDKey, requestID)
+	if alias := c.GetString(requestIDKeyAliasKey); alias != "" {
+		c.Set(alias, requestID)
+	}
+}
+
 // GetRequestID extracts request_id from gin.Context or generates a new one
+// Lookup precedence:
+//  1. request_id stored in gin.Context (by RequestIDMiddleware or SetRequestID)
+//  2. incoming X-Request-ID header if it passes ValidateRequestID, so a client ID
+//     is honored even where the middleware is not installed
+//  3. a newly generated ID
+//
+// Only step 3 differs from GetRequestIDStrict, which never generates and reports a missing ID instead.
 func GetRequestID(c *gin.Context) string {
 	if requestID := c.GetString(RequestIDKey); requestID != "" {
 		return requestID
 	}
-	return uuid.New().String()
+	if c.Request != nil {
+		if requestID := firstHeaderValue(c.Request.Header, HeaderRequestID); ValidateRequestID(requestID) == nil {
+			return requestID
+		}
+	}
+	return NewRequestID()
+}
+
+// GetRequestIDStrict returns the request ID stored by RequestIDMiddleware or SetRequestID, never generating one
+// Unlike GetRequestID it ignores the X-Request-ID header and returns "", false when no ID was stored,
+// logging a warning with the request path. Use it in services that must fail loudly when the
+// middleware is not wired, rather than logging untraceable random IDs.
+//
+// Usage:
+//
+//	requestID, ok := httputil.GetRequestIDStrict(c)
+//	if !ok {
+//		httputil.RespondError(c, http.StatusInternalServerError, "missing_request_id", "request ID middleware not installed")
+//		return
+//	}
+func GetRequestIDStrict(c *gin.Context) (string, bool) {
+	if requestID := requestIDFromContext(c); requestID != "" {
+		return requestID, true
+	}
+
+	path := ""
+	if c.Request != nil && c.Request.URL != nil {
+		path = c.Request.URL.Path
+	}
+	slog.WarnContext(c, "httputil: request ID missing, is RequestIDMiddleware installed?", slog.String("path", path))
+	return "", false
+}
+
+// SetRequestID overwrites request_id in gin.Context and the request context.Context
+// The Config.RequestIDKeyAlias key is updated as well. The X-Request-ID response header is updated too if a prior middleware already set it
+// and the response has not been written yet. The ID is checked with ValidateRequestID.
+//
+// Usage:
+//
+//	if err := httputil.SetRequestID(c, verifiedID); err != nil {
+//		c.AbortWithStatus(http.StatusBadRequest)
+//		return
+//	}
+func SetRequestID(c *gin.Context, requestID string) error {
+	if err := ValidateRequestID(requestID); err != nil {
+		return err
+	}
+
+	setGinRequestID(c, requestID)
+	if c.Request != nil {
+		c.Request = c.Request.WithContext(ContextWithRequestID(c.Request.Context(), requestID))
+	}
+	if !c.Writer.Written() && c.Writer.Header().Get(HeaderRequestID) != "" {
+		c.Header(HeaderRequestID, SanitizeHeaderValue(requestID))
+	}
+	return nil
 }
 
 // GetRequestIDFromContext extracts request_id from context.Context
+// The ID is found through any wrapping (context.WithValue, WithTimeout, ...) of a context from
+// ContextFromGin, the request context or a gin.Context, no *gin.Context type assertion is needed.
+// A bare *gin.Context without stored ID falls back to the X-Request-ID header like GetRequestID.
+// Note: if no request ID is stored, a NEW ID is generated on every call, so two calls
+// on the same context may return different values. Use EnsureRequestID to generate once
+// and remember, or RequestIDFromContext to detect a missing ID.
 func GetRequestIDFromContext(ctx context.Context) string {
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		return requestID
+	}
 	if ginCtx, ok := ctx.(*gin.Context); ok {
 		return GetRequestID(ginCtx)
 	}
+	return NewRequestID()
+}
+
+// RequestIDFromContext returns the stored request_id and whether it was found
+// Unlike GetRequestIDFromContext it never generates a new ID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID := requestIDFromContext(ctx)
+	return requestID, requestID != ""
+}
+
+// EnsureRequestID returns the request ID from ctx, generating and storing one if missing
+// All reads from the returned context yield the same ID. For *gin.Context the ID is
+// stored with c.Set and the same gin.Context is returned.
+//
+// Usage:
+//
+//	requestID, ctx := httputil.EnsureRequestID(ctx)
+//	log.Info("bootstrap started", "request_id", requestID)
+func EnsureRequestID(ctx context.Context) (string, context.Context) {
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		return requestID, ctx
+	}
+
+	if ginCtx, ok := ctx.(*gin.Context); ok {
+		requestID := NewRequestID()
+		ginCtx.Set(RequestIDKey, requestID)
+		return requestID, ginCtx
+	}
+	return reqctx.EnsureRequestID(ctx)
+}
+
+// idsFromContext returns stored request and correlation IDs without generating new ones
+// gin.Context storage is looked up first, then the typed keys
+func idsFromContext(ctx context.Context) (requestID, correlationID string) {
+	requestID = requestIDFromContext(ctx)
+	if correlationID = ginStoreValue(ctx, CorrelationIDKey); correlationID == "" {
+		correlationID = reqctx.GetCorrelationIDFromContext(ctx)
+	}
+	return requestID, correlationID
+}
+
+// requestIDFromContext is the request ID half of idsFromContext
+// It is on the hot path of handlers logging many lines: lookups only, no allocation.
+func requestIDFromContext(ctx context.Context) string {
+	if ginCtx, ok := ctx.(*gin.Context); ok {
+		// fast path for the common case, skips the Value chain of wrapping contexts
+		if requestID := ginCtx.GetString(RequestIDKey); requestID != "" {
+			return requestID
+		}
+	}
+	if requestID := ginStoreValue(ctx, RequestIDKey); requestID != "" {
+		return requestID
+	}
+	requestID, _ := reqctx.RequestIDFromContext(ctx)
+	return requestID
+}
 
-	// Try to extract from context value using typed key
-	if requestID, ok := ctx.Value(requestIDKey).(string); ok && requestID != "" {
+// ginStoreValue returns a string stored with gin.Context.Set, also when the gin.Context is wrapped
+// gin.Context.Value answers string keys from its storage and wrapping contexts delegate Value to it
+func ginStoreValue(ctx context.Context, key string) string {
+	value, _ := ctx.Value(key).(string)
+	return value
+}
+
+// ExtractRequestID resolves the request ID for services behind frameworks we don't control (Echo, chi, ...)
+// Lookup precedence:
+//  1. request ID stored in ctx by this package (typed key or gin.Context)
+//  2. first valid value of the X-Request-ID header in headers
+//  3. a newly generated ID
+//
+// Usage:
+//
+//	ctx := r.Context()
+//	ctx = httputil.ContextWithRequestID(ctx, httputil.ExtractRequestID(ctx, r.Header))
+func ExtractRequestID(ctx context.Context, headers http.Header) string {
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		return requestID
+	}
+	if requestID := firstHeaderValue(headers, HeaderRequestID); ValidateRequestID(requestID) == nil {
 		return requestID
 	}
+	return NewRequestID()
+}
 
-	return uuid.New().String()
+// GetCorrelationIDFromContext extracts correlation_id from context.Context
+// Returns empty string if no correlation ID is set
+func GetCorrelationIDFromContext(ctx context.Context) string {
+	_, correlationID := idsFromContext(ctx)
+	return correlationID
 }
 
 // PropagateRequestIDFromContext adds request ID headers from context.Context
 // Use this when you don't have access to gin.Context but have context with request_id
+// X-Correlation-ID carries the correlation ID if set, otherwise the request ID.
+// X-Parent-Request-ID is sent for contexts from NewChildRequestID,
+// Idempotency-Key for contexts from IdempotencyKeyMiddleware.
+// Baggage members from ContextWithBaggage are sent in the Baggage header,
+// the sampling decision of ContextWithSampled in X-Trace-Sampled.
+// Propagation ignores cancellation: headers are stamped even if ctx is already done and the
+// request fails only in client.Do. DoTraced checks ctx first.
 //
 // Usage:
 //
@@ -58,18 +233,113 @@ func GetRequestIDFromContext(ctx context.Context) string {
 //	httputil.PropagateRequestIDFromContext(ctx, req)
 //	resp, err := client.Do(req)
 func PropagateRequestIDFromContext(ctx context.Context, req *http.Request) {
-	requestID := GetRequestIDFromContext(ctx)
-	req.Header.Set(HeaderRequestID, requestID)
-	req.Header.Set(HeaderCorrelationID, requestID)
+	for key, value := range outgoingHeaders(ctx, DefaultConfig()) {
+		req.Header.Set(key, value)
+	}
+}
+
+// TracingHeadersFromContext returns request ID headers from context.Context as a map
+// Use this with SDKs that accept headers as map[string]string instead of *http.Request
+//
+// Usage:
+//
+//	headers := httputil.TracingHeadersFromContext(ctx)
+//	sdkClient.Call(ctx, params, sdk.WithHeaders(headers))
+func TracingHeadersFromContext(ctx context.Context) map[string]string {
+	return outgoingHeaders(ctx, DefaultConfig())
+}
+
+// outgoingHeaders returns all tracing headers to send downstream under the header names of cfg
+// What gets propagated is defined by reqctx.TracingHeadersFromContext
+func outgoingHeaders(ctx context.Context, cfg Config) map[string]string {
+	if ginCtx, ok := ctx.(*gin.Context); ok {
+		ctx = ContextFromGin(ginCtx)
+	} else {
+		ctx = withWrappedGinIDs(ctx)
+	}
+
+	headers := reqctx.TracingHeadersFromContext(ctx)
+	renameHeader(headers, HeaderRequestID, cfg.RequestIDHeader)
+	renameHeader(headers, HeaderCorrelationID, cfg.CorrelationIDHeader)
+	return headers
+}
+
+// withWrappedGinIDs copies IDs stored in a wrapped gin.Context onto ctx, if ctx has none of its own
+// A *gin.Context wrapped by context.WithValue (e.g. metadata.AppendToOutgoingContext) still answers
+// the string keys of its storage, but reqctx only reads its typed keys.
+func withWrappedGinIDs(ctx context.Context) context.Context {
+	if _, ok := reqctx.RequestIDFromContext(ctx); !ok {
+		if requestID := ginStoreValue(ctx, RequestIDKey); requestID != "" {
+			ctx = reqctx.ContextWithRequestID(ctx, requestID)
+		}
+	}
+	if reqctx.GetCorrelationIDFromContext(ctx) == "" {
+		if correlationID := ginStoreValue(ctx, CorrelationIDKey); correlationID != "" {
+			ctx = reqctx.ContextWithCorrelationID(ctx, correlationID)
+		}
+	}
+	return ctx
+}
+
+// renameHeader moves the value of from to to in headers, a missing from is left missing
+func renameHeader(headers map[string]string, from, to string) {
+	value, ok := headers[from]
+	if from == to || !ok {
+		return
+	}
+	headers[to] = value
+	delete(headers, from)
+}
+
+// coreContext returns ctx in a form reqctx understands
+// A gin.Context is replaced by its request context with the IDs of gin storage on top, nothing is generated
+func coreContext(ctx context.Context) context.Context {
+	ginCtx, ok := ctx.(*gin.Context)
+	if !ok {
+		return ctx
+	}
+
+	core := valueContext(ginCtx)
+	if requestID := ginCtx.GetString(RequestIDKey); requestID != "" {
+		core = reqctx.ContextWithRequestID(core, requestID)
+	}
+	if correlationID := ginCtx.GetString(CorrelationIDKey); correlationID != "" {
+		core = reqctx.ContextWithCorrelationID(core, correlationID)
+	}
+	return core
+}
+
+// valueContext returns the context holding values set by this package
+// For gin.Context it is the request context, where middlewares store typed values
+func valueContext(ctx context.Context) context.Context {
+	if ginCtx, ok := ctx.(*gin.Context); ok {
+		if ginCtx.Request == nil {
+			return context.Background()
+		}
+		return ginCtx.Request.Context()
+	}
+	return ctx
 }
 
 // ContextWithRequestID creates a new context with request_id value
 // Useful for passing request ID to goroutines or async operations
 func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
-	return context.WithValue(ctx, requestIDKey, requestID)
+	return reqctx.ContextWithRequestID(ctx, requestID)
+}
+
+// ContextWithCorrelationID creates a new context with correlation_id value
+// The correlation ID spans the whole transaction while the request ID identifies a single hop
+func ContextWithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return reqctx.ContextWithCorrelationID(ctx, correlationID)
 }
 
 // ContextFromGin creates a new context from gin.Context with request_id propagated
+// correlation_id, tenant_id and user_id are copied too if set in gin.Context, the route template
+// of c.FullPath() is stored for RouteFromContext.
+// The request ID is always stored with ContextWithRequestID, so it resolves from the result and
+// any context derived from it without type assertions to *gin.Context.
+// If c.Request is nil (gin.Context reused outside the HTTP lifecycle) the context is rooted
+// at context.Background() instead of panicking.
 // Use this when calling service methods that need request tracing
 //
 // Usage:
@@ -78,5 +348,36 @@ func ContextWithRequestID(ctx context.Context, requestID string) context.Context
 //	result, err := h.service.DoSomething(ctx, params)
 func ContextFromGin(c *gin.Context) context.Context {
 	requestID := GetRequestID(c)
-	return ContextWithRequestID(c.Request.Context(), requestID)
-}
\ No newline at end of file
+	ctx := ContextWithRequestID(valueContext(c), requestID)
+	if correlationID := c.GetString(CorrelationIDKey); correlationID != "" {
+		ctx = ContextWithCorrelationID(ctx, correlationID)
+	}
+	if tenantID := c.GetString(TenantIDKey); tenantID != "" {
+		ctx = ContextWithTenantID(ctx, tenantID)
+	}
+	if userID := c.GetString(UserIDKey); userID != "" {
+		ctx = ContextWithUserID(ctx, userID)
+	}
+	if route := c.FullPath(); route != "" {
+		ctx = ContextWithRoute(ctx, route)
+	}
+	return ctx
+}
+
+// SameTrace reports whether a and b carry the same request ID and the same correlation ID
+// Correlation IDs are compared only if one of the contexts has it. False if either lacks a request ID.
+// Useful in tests spanning goroutines and for detecting trace context leaking between requests.
+//
+// Usage:
+//
+//	go func(ctx context.Context) {
+//		assert.True(t, httputil.SameTrace(parentCtx, ctx))
+//	}(httputil.DetachContext(parentCtx))
+func SameTrace(a, b context.Context) bool {
+	requestIDA, correlationIDA := idsFromContext(a)
+	requestIDB, correlationIDB := idsFromContext(b)
+	if requestIDA == "" || requestIDB == "" {
+		return false
+	}
+	return requestIDA == requestIDB && correlationIDA == correlationIDB
+}
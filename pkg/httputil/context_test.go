@@ -0,0 +1,176 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestGinContext returns a gin.Context without a request, as reused outside the HTTP lifecycle
+func newTestGinContext() *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	return c
+}
+
+func TestNilRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		stored string
+		call   func(c *gin.Context) string
+	}{
+		{name: "ContextFromGin", call: func(c *gin.Context) string { return GetRequestIDFromContext(ContextFromGin(c)) }},
+		{name: "ContextFromGin stored", stored: "abc", call: func(c *gin.Context) string { return GetRequestIDFromContext(ContextFromGin(c)) }},
+		{name: "GetRequestIDFromContext", call: func(c *gin.Context) string { return GetRequestIDFromContext(c) }},
+		{name: "GetRequestIDFromContext stored", stored: "abc", call: func(c *gin.Context) string { return GetRequestIDFromContext(c) }},
+		{name: "GetRequestID", call: GetRequestID},
+		{name: "TracingHeadersFromContext", call: func(c *gin.Context) string { return TracingHeadersFromContext(c)[HeaderRequestID] }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestGinContext()
+			if c.Request != nil {
+				t.Fatal("test context has a request")
+			}
+			if tt.stored != "" {
+				c.Set(RequestIDKey, tt.stored)
+			}
+
+			got := tt.call(c)
+			switch {
+			case tt.stored != "" && got != tt.stored:
+				t.Errorf("request ID = %q, want %q", got, tt.stored)
+			case ValidateRequestID(got) != nil:
+				t.Errorf("request ID = %q is invalid", got)
+			}
+		})
+	}
+}
+
+type testKey struct{}
+
+func TestContextFromGinWrapped(t *testing.T) {
+	newGin := func() *gin.Context {
+		c := newTestGinContext()
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Set(RequestIDKey, "abc")
+		c.Set(CorrelationIDKey, "corr")
+		return c
+	}
+	wrapValue := func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, testKey{}, "v")
+	}
+	wrapTimeout := func(ctx context.Context) context.Context {
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		t.Cleanup(cancel)
+		return ctx
+	}
+
+	tests := []struct {
+		name string
+		ctx  func() context.Context
+	}{
+		{name: "ContextFromGin", ctx: func() context.Context { return ContextFromGin(newGin()) }},
+		{name: "ContextFromGin WithValue", ctx: func() context.Context { return wrapValue(ContextFromGin(newGin())) }},
+		{name: "ContextFromGin WithTimeout", ctx: func() context.Context { return wrapTimeout(ContextFromGin(newGin())) }},
+		{name: "ContextFromGin nested", ctx: func() context.Context { return wrapTimeout(wrapValue(ContextFromGin(newGin()))) }},
+		{name: "gin.Context WithValue", ctx: func() context.Context { return wrapValue(newGin()) }},
+		{name: "gin.Context WithTimeout", ctx: func() context.Context { return wrapTimeout(newGin()) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx()
+			if got, ok := RequestIDFromContext(ctx); !ok || got != "abc" {
+				t.Errorf("RequestIDFromContext = %q, %v, want abc, true", got, ok)
+			}
+			if got := GetRequestIDFromContext(ctx); got != "abc" {
+				t.Errorf("GetRequestIDFromContext = %q, want abc", got)
+			}
+			if got := GetCorrelationIDFromContext(ctx); got != "corr" {
+				t.Errorf("GetCorrelationIDFromContext = %q, want corr", got)
+			}
+			if got := TracingHeadersFromContext(ctx)[HeaderRequestID]; got != "abc" {
+				t.Errorf("propagated %s = %q, want abc", HeaderRequestID, got)
+			}
+		})
+	}
+}
+
+func BenchmarkGetRequestIDFromContext(b *testing.B) {
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ginCtx.Set(RequestIDKey, "req-1")
+
+	typed := ContextWithRequestID(context.Background(), "req-1")
+	wrapped := typed
+	for i := 0; i < 5; i++ {
+		wrapped = context.WithValue(wrapped, testKey{}, i)
+	}
+
+	for _, bc := range []struct {
+		name string
+		ctx  context.Context
+	}{
+		{name: "gin", ctx: ginCtx},
+		{name: "typed", ctx: typed},
+		{name: "wrapped", ctx: wrapped},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if GetRequestIDFromContext(bc.ctx) != "req-1" {
+					b.Fatal("request ID not found")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetRequestID(b *testing.B) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Set(RequestIDKey, "req-1")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if GetRequestID(c) != "req-1" {
+			b.Fatal("request ID not found")
+		}
+	}
+}
+
+func TestSetRequestIDAlias(t *testing.T) {
+	tests := []struct {
+		name  string
+		alias string
+	}{
+		{name: "no alias"},
+		{name: "gin-contrib alias", alias: GinContribRequestIDKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(RequestIDMiddlewareWithConfig(Config{RequestIDKeyAlias: tt.alias}))
+			router.GET("/", func(c *gin.Context) {
+				if err := SetRequestID(c, "verified-1"); err != nil {
+					t.Fatal(err)
+				}
+				if got := c.GetString(RequestIDKey); got != "verified-1" {
+					t.Errorf("%s = %q, want verified-1", RequestIDKey, got)
+				}
+				if tt.alias == "" {
+					return
+				}
+				if got := c.GetString(tt.alias); got != "verified-1" {
+					t.Errorf("alias %s = %q, want verified-1", tt.alias, got)
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(HeaderRequestID, "incoming-1")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// ContextWithAdditionalCorrelationID records one more correlation ID for requests joining several traces
+// The primary correlation ID stays first. Propagation sends all of them comma-separated in
+// X-Correlation-ID, so services unaware of additional IDs keep reading the primary one.
+// Invalid IDs, IDs containing commas and duplicates are ignored.
+//
+// Usage:
+//
+//	for _, part := range batch {
+//		ctx = httputil.ContextWithAdditionalCorrelationID(ctx, part.CorrelationID)
+//	}
+func ContextWithAdditionalCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return reqctx.ContextWithAdditionalCorrelationID(coreContext(ctx), correlationID)
+}
+
+// CorrelationIDsFromContext returns the primary correlation ID followed by the additional ones
+// Returns nil if ctx has no correlation ID at all
+func CorrelationIDsFromContext(ctx context.Context) []string {
+	return reqctx.CorrelationIDsFromContext(coreContext(ctx))
+}
+
+// contextWithCorrelationHeader stores the IDs after the first one of a correlation header as additional
+func contextWithCorrelationHeader(ctx context.Context, h http.Header, key string) context.Context {
+	first := true
+	for _, value := range headerValues(h, key) {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			if first {
+				first = false
+				continue
+			}
+			ctx = reqctx.ContextWithAdditionalCorrelationID(ctx, part)
+		}
+	}
+	return ctx
+}
@@ -0,0 +1,88 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderRequestDeadline carries the caller's remaining time budget in milliseconds
+// It is a relative timeout, not an absolute timestamp, so clock skew between hosts doesn't matter
+const HeaderRequestDeadline = "X-Request-Deadline"
+
+// DefaultMaxRequestDeadline is the largest X-Request-Deadline honored unless Config.MaxRequestDeadline says otherwise
+const DefaultMaxRequestDeadline = time.Minute
+
+// PropagateDeadline writes X-Request-Deadline with the time left until the ctx deadline
+// Nothing is written if ctx has no deadline
+//
+// Usage:
+//
+//	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+//	httputil.PropagateDeadline(ctx, req)
+func PropagateDeadline(ctx context.Context, req *http.Request) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	remaining := time.Until(deadline).Milliseconds()
+	if remaining < 0 {
+		remaining = 0
+	}
+	req.Header.Set(HeaderRequestDeadline, strconv.FormatInt(remaining, 10))
+}
+
+// ApplyDeadlineFromHeader derives a request context deadline from X-Request-Deadline
+// The deadline is measured from the moment the request is received and capped at
+// DefaultMaxRequestDeadline. Missing or malformed headers leave the context untouched.
+// The derived context keeps request_id and correlation_id.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.ApplyDeadlineFromHeader())
+func ApplyDeadlineFromHeader() gin.HandlerFunc {
+	return ApplyDeadlineFromHeaderWithConfig(DefaultConfig())
+}
+
+// ApplyDeadlineFromHeaderWithConfig is ApplyDeadlineFromHeader honoring the header only from cfg.TrustedProxies
+// The budget is capped at cfg.MaxRequestDeadline, so a caller can shorten the server's own time
+// limits but never extend them. With TrustMode AlwaysRegenerate the header is ignored.
+//
+// Usage:
+//
+//	router.Use(httputil.ApplyDeadlineFromHeaderWithConfig(httputil.Config{
+//		TrustedProxies:     &httputil.TrustedProxyConfig{CIDRs: []string{"10.0.0.0/8"}},
+//		MaxRequestDeadline: 10 * time.Second,
+//	}))
+func ApplyDeadlineFromHeaderWithConfig(cfg Config) gin.HandlerFunc {
+	proxies := newTrustedProxies(cfg.TrustedProxies)
+	maxMillis := cfg.MaxRequestDeadline.Milliseconds()
+	if cfg.MaxRequestDeadline <= 0 {
+		maxMillis = DefaultMaxRequestDeadline.Milliseconds()
+	}
+
+	return func(c *gin.Context) {
+		if cfg.TrustMode == AlwaysRegenerate || !proxies.trusts(c.Request) {
+			c.Next()
+			return
+		}
+		ms, err := strconv.ParseInt(headerGet(c.Request.Header, HeaderRequestDeadline), 10, 64)
+		if err != nil || ms < 0 {
+			c.Next()
+			return
+		}
+		// clamp in milliseconds, multiplying a huge value first would overflow time.Duration
+		if ms > maxMillis {
+			ms = maxMillis
+		}
+
+		ctx, cancel := context.WithTimeout(ContextFromGin(c), time.Duration(ms)*time.Millisecond)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
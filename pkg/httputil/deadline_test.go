@@ -0,0 +1,71 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestApplyDeadlineFromHeader(t *testing.T) {
+	trusted := &TrustedProxyConfig{CIDRs: []string{"10.0.0.0/8"}}
+
+	tests := []struct {
+		name       string
+		cfg        Config
+		remoteAddr string
+		header     string
+		// want is the expected budget, 0 means no deadline
+		want time.Duration
+	}{
+		{name: "no header", want: 0},
+		{name: "malformed", header: "soon", want: 0},
+		{name: "negative", header: "-5", want: 0},
+		{name: "within the maximum", header: "2000", want: 2 * time.Second},
+		{name: "above the default maximum", header: "3600000", want: DefaultMaxRequestDeadline},
+		{name: "overflowing value", header: "9223372036854775807", want: DefaultMaxRequestDeadline},
+		{name: "custom maximum", cfg: Config{MaxRequestDeadline: 5 * time.Second}, header: "10000", want: 5 * time.Second},
+		{name: "trusted proxy", cfg: Config{TrustedProxies: trusted}, remoteAddr: "10.1.2.3:4567", header: "2000", want: 2 * time.Second},
+		{name: "untrusted peer", cfg: Config{TrustedProxies: trusted}, remoteAddr: "203.0.113.7:4567", header: "1", want: 0},
+		{name: "AlwaysRegenerate", cfg: Config{TrustMode: AlwaysRegenerate}, header: "1", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(ApplyDeadlineFromHeaderWithConfig(tt.cfg))
+			var (
+				deadline time.Time
+				ok       bool
+			)
+			router.GET("/", func(c *gin.Context) {
+				deadline, ok = c.Request.Context().Deadline()
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.remoteAddr != "" {
+				req.RemoteAddr = tt.remoteAddr
+			}
+			if tt.header != "" {
+				req.Header.Set(HeaderRequestDeadline, tt.header)
+			}
+			start := time.Now()
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.want == 0 {
+				if ok {
+					t.Fatalf("deadline set in %v, want none", deadline.Sub(start))
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("no deadline, want one")
+			}
+			// the deadline is set after start, so the budget measured from start is slightly longer
+			if budget := deadline.Sub(start); budget < tt.want || budget > tt.want+time.Second {
+				t.Errorf("budget = %v, want about %v", budget, tt.want)
+			}
+		})
+	}
+}
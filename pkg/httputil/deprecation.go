@@ -0,0 +1,59 @@
+package httputil
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HeaderDeprecation marks the endpoint as deprecated
+	HeaderDeprecation = "Deprecation"
+
+	// HeaderSunset carries the date the endpoint stops working (RFC 8594)
+	HeaderSunset = "Sunset"
+
+	// HeaderWarning carries a human-readable warning with the request_id (code 299, miscellaneous persistent warning)
+	HeaderWarning = "Warning"
+)
+
+// DeprecationMiddleware marks responses of a deprecated route with Deprecation, Sunset and Warning headers
+// The Warning is 299 - "<msg> (request_id=<id>)", so clients reporting it quote a traceable ID.
+// Sunset is omitted for a zero sunset. Mount it per route with the message of that route, after
+// RequestIDMiddleware.
+//
+// Usage:
+//
+//	sunset := time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC)
+//	router.GET("/v1/orders/:id", httputil.DeprecationMiddleware(sunset, "use /v2/orders/:id"), h.GetOrderV1)
+//
+// Response headers:
+//
+//	Deprecation: true
+//	Sunset: Mon, 30 Jun 2025 00:00:00 GMT
+//	Warning: 299 - "use /v2/orders/:id (request_id=...)"
+func DeprecationMiddleware(sunset time.Time, msg string) gin.HandlerFunc {
+	var sunsetValue string
+	if !sunset.IsZero() {
+		sunsetValue = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		h.Set(HeaderDeprecation, "true")
+		if sunsetValue != "" {
+			h.Set(HeaderSunset, sunsetValue)
+		}
+		h.Add(HeaderWarning, deprecationWarning(msg, GetRequestID(c)))
+		c.Next()
+	}
+}
+
+// deprecationWarning formats a 299 Warning value, the text is a quoted-string
+func deprecationWarning(msg, requestID string) string {
+	text := SanitizeHeaderValue(msg + " (request_id=" + requestID + ")")
+	text = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text)
+	return `299 - "` + text + `"`
+}
@@ -0,0 +1,63 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// DetachContext returns a background-rooted context carrying only request_id and correlation_id from ctx
+// Cancellation and deadline of ctx are not inherited, so async work started from a request
+// survives the request's completion but keeps its trace identifiers.
+//
+// Usage:
+//
+//	go h.notifier.Send(httputil.DetachContext(ctx), order)
+func DetachContext(ctx context.Context) context.Context {
+	return reqctx.DetachContext(coreContext(ctx))
+}
+
+// CancelableDetached returns a detached context with the tracing values of ctx and its own cancel func
+// A *gin.Context is accepted too. Unlike DetachContext it keeps tenant, baggage and the other values
+// WithTracingFrom copies, and the returned cancel stops it. See reqctx.CancelableDetached.
+//
+// Usage:
+//
+//	ctx, cancel := httputil.CancelableDetached(c)
+//	h.exports.Store(exportID, cancel)
+//	go h.runExport(ctx, exportID)
+func CancelableDetached(ctx context.Context) (context.Context, context.CancelFunc) {
+	return reqctx.CancelableDetached(coreContext(ctx))
+}
+
+// WithTracingFrom copies request_id, correlation_id and baggage from src onto dst
+// Cancellation and deadline of src are not copied. Identifiers missing in src leave dst unchanged,
+// baggage members from src are merged over those of dst.
+//
+// Usage:
+//
+//	for job := range jobs {
+//		ctx := httputil.WithTracingFrom(workerCtx, job.Context)
+//		process(ctx, job)
+//	}
+func WithTracingFrom(dst, src context.Context) context.Context {
+	return reqctx.WithTracingFrom(dst, coreContext(src))
+}
+
+// AsyncContext returns a context for goroutines started from a gin handler
+// It is the c.Copy() pattern done right: values of the request context (request_id, correlation_id,
+// tenant, user, baggage, ...) are kept, cancellation and deadline are not, and no reference to the
+// pooled gin.Context is retained, so it stays valid after the handler returns.
+//
+// Usage:
+//
+//	router.POST("/orders", func(c *gin.Context) {
+//		ctx := httputil.AsyncContext(c)
+//		go h.audit.Record(ctx, "order.created")
+//		c.Status(http.StatusAccepted)
+//	})
+func AsyncContext(c *gin.Context) context.Context {
+	return context.WithoutCancel(ContextFromGin(c))
+}
@@ -0,0 +1,30 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderDryRun marks a request whose whole call chain must skip side effects, "true" or "1"
+// The request ID middlewares read it only with Config.HonorDryRun and from trusted requests,
+// outgoing requests forward it when set.
+const HeaderDryRun = reqctx.HeaderDryRun
+
+// ContextWithDryRun creates a new context with the dry-run flag
+func ContextWithDryRun(ctx context.Context, dryRun bool) context.Context {
+	return reqctx.ContextWithDryRun(ctx, dryRun)
+}
+
+// DryRunFromContext reports whether the request is a dry run, false if no flag is set
+// A *gin.Context is accepted too.
+//
+// Usage:
+//
+//	if httputil.DryRunFromContext(c) {
+//		c.JSON(http.StatusOK, preview)
+//		return
+//	}
+func DryRunFromContext(ctx context.Context) bool {
+	return reqctx.DryRunFromContext(valueContext(ctx))
+}
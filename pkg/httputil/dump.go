@@ -0,0 +1,52 @@
+package httputil
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// redactedValue replaces values of sensitive headers in DumpHeaders output
+const redactedValue = "[REDACTED]"
+
+// defaultRedactKeys are always redacted by DumpHeaders
+var defaultRedactKeys = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// DumpHeaders renders headers as "Key: value" lines sorted by key for debug logging
+// Authorization, Cookie and Set-Cookie are always redacted, redactKeys adds more.
+// Key matching is case-insensitive.
+//
+// Usage:
+//
+//	log.Debug("incoming request", "headers", httputil.DumpHeaders(c.Request.Header, "X-Api-Key"),
+//		"request_id", httputil.GetRequestID(c))
+func DumpHeaders(h http.Header, redactKeys ...string) string {
+	redact := make(map[string]struct{}, len(defaultRedactKeys)+len(redactKeys))
+	for _, key := range defaultRedactKeys {
+		redact[strings.ToLower(key)] = struct{}{}
+	}
+	for _, key := range redactKeys {
+		redact[strings.ToLower(key)] = struct{}{}
+	}
+
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		_, sensitive := redact[strings.ToLower(key)]
+		for _, value := range h[key] {
+			if sensitive {
+				value = redactedValue
+			}
+			b.WriteString(key)
+			b.WriteString(": ")
+			b.WriteString(SanitizeHeaderValue(value))
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
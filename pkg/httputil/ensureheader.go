@@ -0,0 +1,79 @@
+package httputil
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnsureRequestIDHeaderMiddleware guarantees the X-Request-ID response header, however early the response is written
+// The header is set lazily right before the response header is sent, if nothing set it by then:
+// when a middleware mounted before RequestIDMiddleware answers the request itself, or a handler
+// cleared the headers. The value is GetRequestID(c) at that moment. Mount it first.
+//
+// Usage:
+//
+//	router.Use(httputil.EnsureRequestIDHeaderMiddleware(), authMiddleware, httputil.RequestIDMiddleware())
+func EnsureRequestIDHeaderMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w := &requestIDHeaderWriter{ResponseWriter: c.Writer, c: c}
+		c.Writer = w
+		c.Next()
+
+		// gin writes the header of empty responses after the chain through its own writer
+		w.ensureHeader()
+	}
+}
+
+// requestIDHeaderWriter sets the request ID header right before the response header is written
+type requestIDHeaderWriter struct {
+	gin.ResponseWriter
+	c    *gin.Context
+	done bool
+}
+
+// ensureHeader sets the request ID header once if missing, as long as the header has not been sent yet
+func (w *requestIDHeaderWriter) ensureHeader() {
+	if w.done || w.ResponseWriter.Written() {
+		return
+	}
+	w.done = true
+	if w.Header().Get(HeaderRequestID) == "" {
+		w.Header().Set(HeaderRequestID, SanitizeHeaderValue(GetRequestID(w.c)))
+	}
+}
+
+// WriteHeader implements http.ResponseWriter
+func (w *requestIDHeaderWriter) WriteHeader(code int) {
+	w.ensureHeader()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// WriteHeaderNow implements gin.ResponseWriter
+func (w *requestIDHeaderWriter) WriteHeaderNow() {
+	w.ensureHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write implements http.ResponseWriter
+func (w *requestIDHeaderWriter) Write(b []byte) (int, error) {
+	w.ensureHeader()
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString implements gin.ResponseWriter
+func (w *requestIDHeaderWriter) WriteString(s string) (int, error) {
+	w.ensureHeader()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush implements http.Flusher
+func (w *requestIDHeaderWriter) Flush() {
+	w.ensureHeader()
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (w *requestIDHeaderWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
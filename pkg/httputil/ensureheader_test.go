@@ -0,0 +1,115 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestEnsureRequestIDHeaderMiddleware(t *testing.T) {
+	tests := []struct {
+		name string
+		// early answers the request from a middleware mounted before RequestIDMiddleware, if set
+		early gin.HandlerFunc
+		// handler answers the request after RequestIDMiddleware
+		handler    gin.HandlerFunc
+		wantStatus int
+		wantID     string
+	}{
+		{
+			name:       "AbortWithStatus",
+			early:      func(c *gin.Context) { c.AbortWithStatus(http.StatusUnauthorized) },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "String",
+			early:      func(c *gin.Context) { c.String(http.StatusForbidden, "denied"); c.Abort() },
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "WriteString",
+			early:      func(c *gin.Context) { _, _ = c.Writer.WriteString("ok"); c.Abort() },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "WriteHeader then Write",
+			early: func(c *gin.Context) {
+				c.Writer.WriteHeader(http.StatusTooManyRequests)
+				_, _ = c.Writer.Write([]byte("slow down"))
+				c.Abort()
+			},
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "Write",
+			early:      func(c *gin.Context) { _, _ = c.Writer.Write([]byte("ok")); c.Abort() },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Flush",
+			early:      func(c *gin.Context) { c.Writer.Flush(); c.Abort() },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "WriteHeaderNow",
+			early:      func(c *gin.Context) { c.Status(http.StatusAccepted); c.Writer.WriteHeaderNow(); c.Abort() },
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "empty response",
+			early:      func(c *gin.Context) { c.Status(http.StatusNoContent); c.Abort() },
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "explicit header kept",
+			early: func(c *gin.Context) {
+				c.Header(HeaderRequestID, "explicit")
+				c.AbortWithStatus(http.StatusUnauthorized)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantID:     "explicit",
+		},
+		{
+			name: "headers cleared by the handler",
+			handler: func(c *gin.Context) {
+				c.Writer.Header().Del(HeaderRequestID)
+				c.String(http.StatusOK, GetRequestID(c))
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(EnsureRequestIDHeaderMiddleware())
+			if tt.early != nil {
+				router.Use(tt.early)
+			}
+			router.Use(RequestIDMiddleware())
+			handler := tt.handler
+			if handler == nil {
+				handler = func(c *gin.Context) { t.Error("handler reached") }
+			}
+			router.GET("/", handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			ids := w.Header().Values(HeaderRequestID)
+			if len(ids) != 1 || ids[0] == "" {
+				t.Fatalf("%s = %q, want one value", HeaderRequestID, ids)
+			}
+			if tt.wantID != "" && ids[0] != tt.wantID {
+				t.Errorf("%s = %q, want %q", HeaderRequestID, ids[0], tt.wantID)
+			}
+			if tt.handler != nil && ids[0] != w.Body.String() {
+				t.Errorf("%s = %q, want the handler's request ID %q", HeaderRequestID, ids[0], w.Body.String())
+			}
+		})
+	}
+}
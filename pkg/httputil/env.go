@@ -0,0 +1,33 @@
+package httputil
+
+import (
+	"context"
+	"os/exec"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+const (
+	// EnvRequestID is the environment variable carrying the request ID into subprocesses
+	EnvRequestID = reqctx.EnvRequestID
+
+	// EnvCorrelationID is the environment variable carrying the correlation ID into subprocesses
+	EnvCorrelationID = reqctx.EnvCorrelationID
+)
+
+// PropagateToEnv appends REQUEST_ID and CORRELATION_ID from ctx to the environment of cmd
+// A *gin.Context is accepted too. See reqctx.PropagateToEnv.
+//
+// Usage:
+//
+//	cmd := exec.CommandContext(c, "convert", in, out)
+//	httputil.PropagateToEnv(c, cmd)
+//	err := cmd.Run()
+func PropagateToEnv(ctx context.Context, cmd *exec.Cmd) {
+	reqctx.PropagateToEnv(coreContext(ctx), cmd)
+}
+
+// ContextFromEnv builds a background context from REQUEST_ID and CORRELATION_ID, for the child process
+func ContextFromEnv() context.Context {
+	return reqctx.ContextFromEnv()
+}
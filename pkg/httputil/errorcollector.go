@@ -0,0 +1,81 @@
+package httputil
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCollectorConfig configures ErrorCollectorMiddlewareWithConfig
+type ErrorCollectorConfig struct {
+	// Logger receives one record per collected error, slog.Default() is used if nil
+	Logger *slog.Logger
+
+	// Respond writes an ErrorResponse for the last collected error if the handler wrote nothing
+	// Status and code are looked up as in RespondWithError
+	Respond bool
+}
+
+// ErrorCollectorMiddleware logs every error added with c.Error after the handler returns
+// Records carry error, method, path and request_id so collected errors are always traceable.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.ErrorCollectorMiddleware(logger))
+//
+//	router.GET("/orders/:id", func(c *gin.Context) {
+//		if err := h.service.Sync(c); err != nil {
+//			_ = c.Error(err)
+//		}
+//		c.JSON(http.StatusOK, order)
+//	})
+func ErrorCollectorMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return ErrorCollectorMiddlewareWithConfig(ErrorCollectorConfig{Logger: logger})
+}
+
+// ErrorCollectorMiddlewareWithConfig is ErrorCollectorMiddleware with an optional error response
+// With Respond, handlers can just call c.Error(err) and return, the middleware answers for them.
+//
+// Usage:
+//
+//	router.Use(httputil.ErrorCollectorMiddlewareWithConfig(httputil.ErrorCollectorConfig{
+//		Logger:  logger,
+//		Respond: true,
+//	}))
+//
+//	router.GET("/orders/:id", func(c *gin.Context) {
+//		order, err := h.service.GetOrder(c, c.Param("id"))
+//		if err != nil {
+//			_ = c.Error(err)
+//			return
+//		}
+//		c.JSON(http.StatusOK, order)
+//	})
+func ErrorCollectorMiddlewareWithConfig(cfg ErrorCollectorConfig) gin.HandlerFunc {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+
+		requestID := GetRequestID(c)
+		for _, err := range c.Errors {
+			logger.LogAttrs(c.Request.Context(), slog.LevelError, "handler error",
+				slog.String("error", err.Error()),
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.Request.URL.Path),
+				slog.String(LogKeyRequestID, requestID),
+			)
+		}
+
+		if cfg.Respond && !c.Writer.Written() {
+			RespondWithError(c, c.Errors.Last().Err)
+		}
+	}
+}
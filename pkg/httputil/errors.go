@@ -0,0 +1,109 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponse is the standard error response body
+type ErrorResponse struct {
+	Error     ErrorBody `json:"error"`
+	RequestID string    `json:"request_id"`
+}
+
+// ErrorBody describes an error in ErrorResponse
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// errorMapping maps a sentinel error to a response status, code and public message
+type errorMapping struct {
+	target  error
+	status  int
+	code    string
+	message string
+}
+
+var (
+	errorMappingsMu sync.RWMutex
+	errorMappings   = []errorMapping{
+		{target: context.DeadlineExceeded, status: http.StatusGatewayTimeout, code: "timeout", message: "Request timed out"},
+	}
+)
+
+// RegisterErrorStatus makes RespondWithError answer errors matching target (errors.Is) with status, code and message
+// message is sent to clients instead of the error text, which may carry wrapped internal details;
+// empty uses http.StatusText(status). Mappings are checked in registration order.
+// Safe for concurrent use, usually called at startup.
+//
+// Usage:
+//
+//	httputil.RegisterErrorStatus(service.ErrOrderNotFound, http.StatusNotFound, "order_not_found", "Order not found")
+func RegisterErrorStatus(target error, status int, code, message string) {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	errorMappingsMu.Lock()
+	defer errorMappingsMu.Unlock()
+	errorMappings = append(errorMappings, errorMapping{target: target, status: status, code: code, message: message})
+}
+
+// RespondError aborts the request with a JSON ErrorResponse carrying the request_id
+// The X-Request-ID response header is set too, so the ID is always surfaced to clients.
+//
+// Usage:
+//
+//	httputil.RespondError(c, http.StatusBadRequest, "invalid_request", err.Error())
+//
+// Response:
+//
+//	{"error": {"code": "invalid_request", "message": "..."}, "request_id": "..."}
+func RespondError(c *gin.Context, status int, code string, message string) {
+	requestID := GetRequestID(c)
+	c.Header(HeaderRequestID, SanitizeHeaderValue(requestID))
+	c.AbortWithStatusJSON(status, ErrorResponse{
+		Error:     ErrorBody{Code: code, Message: message},
+		RequestID: requestID,
+	})
+}
+
+// RespondWithError is RespondError with status, code and message looked up by RegisterErrorStatus
+// The error text is never sent, unknown errors are answered with 500 and a generic message.
+//
+// Usage:
+//
+//	order, err := h.service.GetOrder(ctx, id)
+//	if err != nil {
+//		httputil.RespondWithError(c, err)
+//		return
+//	}
+func RespondWithError(c *gin.Context, err error) {
+	if m, ok := lookupErrorMapping(err); ok {
+		RespondError(c, m.status, m.code, m.message)
+		return
+	}
+	RespondError(c, http.StatusInternalServerError, "internal_error", "Internal server error")
+}
+
+// lookupErrorMapping returns the first RegisterErrorStatus mapping matching err
+func lookupErrorMapping(err error) (errorMapping, bool) {
+	if err == nil {
+		return errorMapping{}, false
+	}
+
+	errorMappingsMu.RLock()
+	defer errorMappingsMu.RUnlock()
+
+	for _, m := range errorMappings {
+		if errors.Is(err, m.target) {
+			return m, true
+		}
+	}
+	return errorMapping{}, false
+}
@@ -0,0 +1,88 @@
+package httputil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// withErrorMappings restores the registered error mappings when t ends
+func withErrorMappings(t *testing.T) {
+	t.Helper()
+	errorMappingsMu.RLock()
+	saved := append([]errorMapping(nil), errorMappings...)
+	errorMappingsMu.RUnlock()
+	t.Cleanup(func() {
+		errorMappingsMu.Lock()
+		errorMappings = saved
+		errorMappingsMu.Unlock()
+	})
+}
+
+func TestRespondWithError(t *testing.T) {
+	withErrorMappings(t)
+	errNotFound := errors.New("order not found")
+	errConflict := errors.New("conflict")
+	RegisterErrorStatus(errNotFound, http.StatusNotFound, "order_not_found", "Order not found")
+	RegisterErrorStatus(errConflict, http.StatusConflict, "conflict", "")
+
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{
+			name:        "registered message",
+			err:         fmt.Errorf("load order 42 from db-primary.internal: %w", errNotFound),
+			wantStatus:  http.StatusNotFound,
+			wantCode:    "order_not_found",
+			wantMessage: "Order not found",
+		},
+		{
+			name:        "empty message uses the status text",
+			err:         fmt.Errorf("row version 7: %w", errConflict),
+			wantStatus:  http.StatusConflict,
+			wantCode:    "conflict",
+			wantMessage: http.StatusText(http.StatusConflict),
+		},
+		{
+			name:        "default deadline mapping",
+			err:         fmt.Errorf("query billing: %w", context.DeadlineExceeded),
+			wantStatus:  http.StatusGatewayTimeout,
+			wantCode:    "timeout",
+			wantMessage: "Request timed out",
+		},
+		{
+			name:        "unknown error",
+			err:         errors.New("dial tcp 10.0.0.5:5432: connection refused"),
+			wantStatus:  http.StatusInternalServerError,
+			wantCode:    "internal_error",
+			wantMessage: "Internal server error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			RespondWithError(c, tt.err)
+
+			var body ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != tt.wantStatus || body.Error.Code != tt.wantCode || body.Error.Message != tt.wantMessage {
+				t.Errorf("got %d %q %q, want %d %q %q", w.Code, body.Error.Code, body.Error.Message,
+					tt.wantStatus, tt.wantCode, tt.wantMessage)
+			}
+		})
+	}
+}
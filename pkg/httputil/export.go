@@ -0,0 +1,57 @@
+package httputil
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// exportNames maps tag keys to destination field names, see SetExportTagNames
+var exportNames atomic.Pointer[map[string]string]
+
+// SetExportTagNames renames ExportTags keys for a log destination expecting its own field names
+// Keys are request_id, correlation_id, tenant_id and user_id, unmapped keys keep their name.
+// nil restores the identity mapping. The map is copied. Safe for concurrent use.
+//
+// Usage:
+//
+//	httputil.SetExportTagNames(map[string]string{
+//		httputil.LogKeyRequestID:     "dd.trace_id",
+//		httputil.LogKeyCorrelationID: "dd.correlation_id",
+//	})
+func SetExportTagNames(names map[string]string) {
+	if names == nil {
+		exportNames.Store(nil)
+		return
+	}
+	copied := make(map[string]string, len(names))
+	for key, name := range names {
+		copied[key] = name
+	}
+	exportNames.Store(&copied)
+}
+
+// ExportTags returns the request identifiers of ctx under the field names of SetExportTagNames
+// Same tags as QueryTagsFromContext: only those present, nothing is generated. For log encoders
+// and hooks shipping to SaaS tools (Datadog, New Relic, ...) that correlate by fixed field names.
+//
+// Usage:
+//
+//	for name, value := range httputil.ExportTags(ctx) {
+//		entry.Fields[name] = value
+//	}
+func ExportTags(ctx context.Context) map[string]string {
+	tags, _ := QueryTagsFromContext(ctx)
+
+	names := exportNames.Load()
+	if names == nil {
+		return tags
+	}
+	exported := make(map[string]string, len(tags))
+	for key, value := range tags {
+		if name, ok := (*names)[key]; ok && name != "" {
+			key = name
+		}
+		exported[key] = value
+	}
+	return exported
+}
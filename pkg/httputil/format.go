@@ -0,0 +1,78 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// B3 and Datadog propagation headers, see reqctx
+const (
+	HeaderB3                      = reqctx.HeaderB3
+	HeaderB3TraceID               = reqctx.HeaderB3TraceID
+	HeaderB3SpanID                = reqctx.HeaderB3SpanID
+	HeaderB3ParentSpanID          = reqctx.HeaderB3ParentSpanID
+	HeaderB3Sampled               = reqctx.HeaderB3Sampled
+	HeaderB3Flags                 = reqctx.HeaderB3Flags
+	HeaderDatadogTraceID          = reqctx.HeaderDatadogTraceID
+	HeaderDatadogParentID         = reqctx.HeaderDatadogParentID
+	HeaderDatadogSamplingPriority = reqctx.HeaderDatadogSamplingPriority
+)
+
+// Format encodes the tracing headers into a wire format and back, see reqctx.Format
+type Format = reqctx.Format
+
+// Propagation formats, see reqctx
+var (
+	NativeFormat   = reqctx.NativeFormat
+	B3MultiFormat  = reqctx.B3MultiFormat
+	B3SingleFormat = reqctx.B3SingleFormat
+	DatadogFormat  = reqctx.DatadogFormat
+)
+
+// InjectFormats writes the tracing identifiers of ctx to carrier in each of formats, a *gin.Context is accepted too
+// Without formats it is Inject, see reqctx.InjectFormats.
+//
+// Usage:
+//
+//	httputil.InjectFormats(c, reqctx.HeaderCarrier(req.Header), httputil.NativeFormat, httputil.DatadogFormat)
+func InjectFormats(ctx context.Context, carrier Carrier, formats ...Format) {
+	if len(formats) == 0 {
+		formats = []Format{NativeFormat}
+	}
+	headers := TracingHeadersFromContext(ctx)
+	for _, format := range formats {
+		format.Encode(headers, carrier)
+	}
+}
+
+// ExtractFormats stores the tracing identifiers of carrier in ctx, reading each of formats, see reqctx.ExtractFormats
+func ExtractFormats(ctx context.Context, carrier Extractor, formats ...Format) context.Context {
+	return reqctx.ExtractFormats(ctx, carrier, formats...)
+}
+
+// ExtractFormatsWithOptions is ExtractFormats honoring the carrier values opts allows, see reqctx.ExtractFormatsWithOptions
+func ExtractFormatsWithOptions(ctx context.Context, carrier Extractor, opts ExtractOptions, formats ...Format) context.Context {
+	return reqctx.ExtractFormatsWithOptions(ctx, carrier, opts, formats...)
+}
+
+// formatHeaders encodes headers, named as in cfg, in each of formats
+// NativeFormat keeps the header names of cfg, the other formats get the default names they expect.
+func formatHeaders(headers map[string]string, cfg Config, formats []Format) map[string]string {
+	native := make(map[string]string, len(headers))
+	for key, value := range headers {
+		native[key] = value
+	}
+	renameHeader(native, cfg.RequestIDHeader, HeaderRequestID)
+	renameHeader(native, cfg.CorrelationIDHeader, HeaderCorrelationID)
+
+	encoded := make(reqctx.MapCarrier, len(headers))
+	for _, format := range formats {
+		if format == NativeFormat {
+			NativeFormat.Encode(headers, encoded)
+			continue
+		}
+		format.Encode(native, encoded)
+	}
+	return encoded
+}
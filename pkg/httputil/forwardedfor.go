@@ -0,0 +1,74 @@
+package httputil
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// CanonicalizeForwardedFor returns the addresses of all X-Forwarded-For headers, leftmost first, without duplicates
+// Entries are trimmed and may carry a port or IPv6 brackets ("[2001:db8::1]:443"), IPv4-mapped IPv6
+// addresses are unmapped. Invalid entries are dropped with a debug log. The chain is client-supplied,
+// use it for audit logs, ClientIPFromContext for decisions. AccessLogMiddleware logs it as forwarded_for.
+//
+// Usage:
+//
+//	for _, ip := range httputil.CanonicalizeForwardedFor(r.Header) {
+//		audit.AddHop(ip)
+//	}
+func CanonicalizeForwardedFor(h http.Header) []net.IP {
+	var (
+		ips  []net.IP
+		seen = make(map[netip.Addr]bool)
+	)
+	for _, value := range headerValues(h, "X-Forwarded-For") {
+		for _, hop := range strings.Split(value, ",") {
+			hop = strings.TrimSpace(hop)
+			if hop == "" {
+				continue
+			}
+			addr, ok := parseForwardedHop(hop)
+			if !ok {
+				slog.Debug("httputil: invalid X-Forwarded-For entry dropped", slog.String("entry", SanitizeHeaderValue(hop)))
+				continue
+			}
+			if seen[addr] {
+				continue
+			}
+			seen[addr] = true
+			ips = append(ips, net.IP(addr.AsSlice()))
+		}
+	}
+	return ips
+}
+
+// parseForwardedHop parses an X-Forwarded-For entry with or without a port
+func parseForwardedHop(hop string) (netip.Addr, bool) {
+	if addr, err := netip.ParseAddr(hop); err == nil {
+		return addr.Unmap(), true
+	}
+	if addrPort, err := netip.ParseAddrPort(hop); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	if host, ok := strings.CutPrefix(hop, "["); ok {
+		if addr, err := netip.ParseAddr(strings.TrimSuffix(host, "]")); err == nil {
+			return addr.Unmap(), true
+		}
+	}
+	return netip.Addr{}, false
+}
+
+// forwardedForAttr returns the canonical X-Forwarded-For chain of r as a log attribute
+func forwardedForAttr(r *http.Request) (slog.Attr, bool) {
+	ips := CanonicalizeForwardedFor(r.Header)
+	if len(ips) == 0 {
+		return slog.Attr{}, false
+	}
+	hops := make([]string, len(ips))
+	for i, ip := range ips {
+		hops[i] = ip.String()
+	}
+	return slog.Any("forwarded_for", hops), true
+}
@@ -0,0 +1,117 @@
+package httputil
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderCloudTraceContext is the trace header injected by Google Cloud load balancers and Cloud Run
+// Format: "TRACE_ID/SPAN_ID;o=TRACE_TRUE", span and options are optional.
+const HeaderCloudTraceContext = "X-Cloud-Trace-Context"
+
+const (
+	// LogKeyCloudTrace is the Cloud Logging field linking a log entry to its trace
+	LogKeyCloudTrace = "logging.googleapis.com/trace"
+
+	// LogKeyCloudSpanID is the Cloud Logging field with the span ID as 16 hex digits
+	LogKeyCloudSpanID = "logging.googleapis.com/spanId"
+
+	// LogKeyCloudTraceSampled is the Cloud Logging field with the sampling decision
+	LogKeyCloudTraceSampled = "logging.googleapis.com/trace_sampled"
+)
+
+// CloudTrace is a parsed X-Cloud-Trace-Context header
+type CloudTrace struct {
+	// TraceID is the 32 hex digit trace ID
+	TraceID string
+
+	// SpanID is the decimal span ID, empty if the header has none
+	SpanID string
+
+	// Sampled reports whether the header has o=1
+	Sampled bool
+}
+
+// cloudTraceValue holds the incoming X-Cloud-Trace-Context
+var cloudTraceValue = reqctx.NewContextValue[CloudTrace]("cloud_trace")
+
+// cloudTraceProject is the project ID set by SetCloudTraceProjectID
+var cloudTraceProject atomic.Pointer[string]
+
+// SetCloudTraceProjectID enables Cloud Logging trace fields in LoggerFromContext and ContextHandler
+// Records of requests carrying X-Cloud-Trace-Context (with Config.UseCloudTraceContext) get
+// logging.googleapis.com/trace as "projects/<projectID>/traces/<trace id>", spanId and trace_sampled,
+// so Cloud Logging groups them under the request trace. Empty disables the fields. Safe for concurrent use.
+//
+// Usage:
+//
+//	httputil.SetCloudTraceProjectID(os.Getenv("GOOGLE_CLOUD_PROJECT"))
+func SetCloudTraceProjectID(projectID string) {
+	if projectID == "" {
+		cloudTraceProject.Store(nil)
+		return
+	}
+	cloudTraceProject.Store(&projectID)
+}
+
+// CloudTraceFromContext returns the X-Cloud-Trace-Context recorded with Config.UseCloudTraceContext
+// A *gin.Context is accepted too.
+func CloudTraceFromContext(ctx context.Context) (CloudTrace, bool) {
+	return cloudTraceValue.Get(valueContext(ctx))
+}
+
+// ParseCloudTraceContext parses an X-Cloud-Trace-Context value, ok is false if the trace ID is malformed
+// Invalid span IDs and options are ignored.
+func ParseCloudTraceContext(header string) (trace CloudTrace, ok bool) {
+	value, options, _ := strings.Cut(strings.TrimSpace(header), ";")
+	traceID, spanID, _ := strings.Cut(value, "/")
+	if !isTraceID(traceID) {
+		return CloudTrace{}, false
+	}
+
+	trace.TraceID = strings.ToLower(traceID)
+	if _, err := strconv.ParseUint(spanID, 10, 64); err == nil {
+		trace.SpanID = spanID
+	}
+	trace.Sampled = strings.TrimSpace(options) == "o=1"
+	return trace, true
+}
+
+// isTraceID reports whether s is 32 hex digits and not all zeros
+func isTraceID(s string) bool {
+	if len(s) != 32 || strings.Trim(s, "0") == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// cloudTraceAttrs returns the Cloud Logging trace fields for ctx, nil unless SetCloudTraceProjectID was called
+func cloudTraceAttrs(ctx context.Context) []slog.Attr {
+	projectID := cloudTraceProject.Load()
+	if projectID == nil {
+		return nil
+	}
+	trace, ok := CloudTraceFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	attrs := []slog.Attr{slog.String(LogKeyCloudTrace, "projects/"+*projectID+"/traces/"+trace.TraceID)}
+	if spanID, err := strconv.ParseUint(trace.SpanID, 10, 64); err == nil {
+		// Cloud Logging expects 16 hex digits, the header carries the span ID in decimal
+		attrs = append(attrs, slog.String(LogKeyCloudSpanID, fmt.Sprintf("%016x", spanID)))
+	}
+	return append(attrs, slog.Bool(LogKeyCloudTraceSampled, trace.Sampled))
+}
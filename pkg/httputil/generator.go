@@ -0,0 +1,44 @@
+package httputil
+
+import "github.com/TRAD3R/common/pkg/reqctx"
+
+// IDGenerator generates new request IDs
+type IDGenerator = reqctx.IDGenerator
+
+// IDGeneratorFunc adapts an ordinary function to IDGenerator
+type IDGeneratorFunc = reqctx.IDGeneratorFunc
+
+// UUIDGenerator generates random (v4) UUIDs, it is the default generator
+type UUIDGenerator = reqctx.UUIDGenerator
+
+// ShortIDGenerator generates short human-quotable IDs like "7K3QX9MVDA", see reqctx.ShortIDGenerator
+type ShortIDGenerator = reqctx.ShortIDGenerator
+
+// SetIDGenerator replaces the generator used for all new request IDs
+// Passing nil restores the default UUIDGenerator. Safe for concurrent use.
+//
+// Usage:
+//
+//	httputil.SetIDGenerator(httputil.IDGeneratorFunc(func() string {
+//		return uuid.Must(uuid.NewV7()).String()
+//	}))
+func SetIDGenerator(gen IDGenerator) {
+	reqctx.SetIDGenerator(gen)
+}
+
+// GetIDGenerator returns the generator currently used for new request IDs
+func GetIDGenerator() IDGenerator {
+	return reqctx.GetIDGenerator()
+}
+
+// SetServicePrefix makes generated request IDs look like "<name>-<id>", e.g. "payments-<uuid>"
+// so any log line tells which service started the trace. Incoming IDs are never modified.
+// An empty name disables the prefix. Safe for concurrent use.
+func SetServicePrefix(name string) {
+	reqctx.SetServicePrefix(name)
+}
+
+// NewRequestID generates a new request ID with the configured generator and service prefix
+func NewRequestID() string {
+	return reqctx.NewRequestID()
+}
@@ -0,0 +1,62 @@
+package httputil
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GinLogFormatter is gin's default access log format with request_id appended
+// The request ID is read from param.Keys, so RequestIDMiddleware may be mounted before or after
+// gin's logger: gin reads the keys once the handler chain returns. Requests without a request ID
+// show request_id=-. Register it with gin.LoggerWithFormatter or use GinLogger.
+//
+// Usage:
+//
+//	router := gin.New()
+//	router.Use(gin.LoggerWithFormatter(httputil.GinLogFormatter), httputil.RequestIDMiddleware())
+//
+//	// or with gin.LoggerWithConfig
+//	router.Use(gin.LoggerWithConfig(gin.LoggerConfig{
+//		Formatter: httputil.GinLogFormatter,
+//		SkipPaths: []string{"/healthz"},
+//	}))
+func GinLogFormatter(param gin.LogFormatterParams) string {
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+
+	requestID, _ := param.Keys[RequestIDKey].(string)
+	if requestID == "" {
+		requestID = "-"
+	}
+
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v | request_id=%s\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		param.Path,
+		SanitizeHeaderValue(requestID),
+		param.ErrorMessage,
+	)
+}
+
+// GinLogger returns gin's logger middleware writing the GinLogFormatter format to gin.DefaultWriter
+//
+// Usage:
+//
+//	router := gin.New()
+//	router.Use(httputil.GinLogger(), httputil.RequestIDMiddleware(), gin.Recovery())
+func GinLogger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(GinLogFormatter)
+}
@@ -0,0 +1,59 @@
+package httputil
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// RequestIDHandler is the net/http counterpart of RequestIDMiddleware
+// Request and correlation IDs are stored in the request context.Context and echoed in the response headers.
+// Members of an incoming Baggage header are available via BaggageFromContext,
+// the X-Trace-Sampled decision of trusted requests via IsSampled.
+//
+// Usage:
+//
+//	mux := http.NewServeMux()
+//	http.ListenAndServe(":8080", httputil.RequestIDHandler(mux))
+func RequestIDHandler(next http.Handler) http.Handler {
+	return RequestIDHandlerWithConfig(DefaultConfig())(next)
+}
+
+// RequestIDHandlerWithConfig returns a RequestIDHandler middleware with custom header names
+//
+// Usage:
+//
+//	handler := httputil.RequestIDHandlerWithConfig(httputil.Config{RequestIDHeader: "Request-Id"})(mux)
+func RequestIDHandlerWithConfig(cfg Config) func(http.Handler) http.Handler {
+	cfg = cfg.withDefaults()
+	cfg.ResponseHeaders = cfg.responseHeaders()
+	proxies := newTrustedProxies(cfg.TrustedProxies)
+	skip := newPathMatcher(cfg.SkipPaths)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip.match(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ids := resolveIDs(r, cfg, proxies)
+			ctx := incomingContext(r, cfg, ids, proxies)
+
+			writeResponseHeaders(w.Header(), cfg, ids, ctx)
+
+			if cfg.RequireCorrelationID && !ids.correlationIncoming {
+				writeJSON(w, http.StatusBadRequest, missingCorrelationIDBody(cfg, ids.requestID))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,50 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderParentRequestID carries the request ID of the caller that spawned the request
+const HeaderParentRequestID = reqctx.HeaderParentRequestID
+
+// NewChildRequestID generates a request ID for an outbound call and records the current one as its parent
+// The returned context carries the child as request_id, the current request ID as parent and an
+// unchanged correlation_id (the current request ID becomes the correlation ID if none is set),
+// so the whole tree shares one correlation ID. Propagation sends the parent in X-Parent-Request-ID.
+//
+// Usage:
+//
+//	childID, callCtx := httputil.NewChildRequestID(ctx)
+//	req, _ := http.NewRequestWithContext(callCtx, "GET", url, nil)
+//	log.Info("calling inventory", "child_request_id", childID)
+func NewChildRequestID(ctx context.Context) (string, context.Context) {
+	if ginCtx, ok := ctx.(*gin.Context); ok {
+		ctx = ContextFromGin(ginCtx)
+	}
+	return reqctx.NewChildRequestID(ctx)
+}
+
+// ContextWithParentRequestID creates a new context with parent_request_id value
+func ContextWithParentRequestID(ctx context.Context, parentID string) context.Context {
+	return reqctx.ContextWithParentRequestID(ctx, parentID)
+}
+
+// ParentRequestIDFromContext returns the parent request ID and whether it was found
+func ParentRequestIDFromContext(ctx context.Context) (string, bool) {
+	return reqctx.ParentRequestIDFromContext(valueContext(ctx))
+}
+
+// NewJobContext returns a background context with a synthetic request ID for a cron or scheduled job
+// The ID looks like "cron-cleanup-<uuid>" for jobName "cron-cleanup", see reqctx.NewJobContext.
+//
+// Usage:
+//
+//	ctx := httputil.NewJobContext("cron-cleanup")
+//	logger := httputil.LoggerFromContext(ctx, slog.Default())
+func NewJobContext(jobName string) context.Context {
+	return reqctx.NewJobContext(jobName)
+}
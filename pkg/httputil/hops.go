@@ -0,0 +1,58 @@
+package httputil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderRequestHops carries the number of services a request has passed, incremented on every propagation
+// The request ID middlewares read it into the request context, outgoing requests send it plus one.
+const HeaderRequestHops = reqctx.HeaderRequestHops
+
+// ContextWithHopCount creates a new context with the hop count of the current service
+func ContextWithHopCount(ctx context.Context, hops int) context.Context {
+	return reqctx.ContextWithHopCount(ctx, hops)
+}
+
+// HopCountFromContext returns the number of services the request passed before this one, 0 at the origin
+// A *gin.Context is accepted too. See reqctx.HopCountFromContext.
+func HopCountFromContext(ctx context.Context) int {
+	return reqctx.HopCountFromContext(valueContext(ctx))
+}
+
+// MaxHops rejects requests that passed more than n services with 508 Loop Detected
+// A request calling back into a service it came from keeps incrementing X-Request-Hops, so a
+// misrouted loop is cut off after n hops instead of exhausting the mesh. The count is read from
+// the request context, or from the header if the request ID middleware hasn't run yet.
+// It panics at startup if n is negative.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.MaxHops(16))
+//
+// Response:
+//
+//	{"error": {"code": "loop_detected", "message": "Request passed 17 services, limit is 16"}, "request_id": "..."}
+func MaxHops(n int) gin.HandlerFunc {
+	if n < 0 {
+		panic("httputil: MaxHops limit is negative")
+	}
+
+	return func(c *gin.Context) {
+		hops := HopCountFromContext(c)
+		if header, ok := reqctx.ParseHopCount(c.GetHeader(HeaderRequestHops)); ok && hops == 0 {
+			hops = header
+		}
+		if hops > n {
+			RespondError(c, http.StatusLoopDetected, "loop_detected",
+				fmt.Sprintf("Request passed %d services, limit is %d", hops, n))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderIdempotencyKey carries the client-chosen key used to deduplicate retried requests
+const HeaderIdempotencyKey = reqctx.HeaderIdempotencyKey
+
+// MaxIdempotencyKeyLength is the maximum accepted length of an idempotency key
+const MaxIdempotencyKeyLength = reqctx.MaxIdempotencyKeyLength
+
+// ErrInvalidIdempotencyKey is returned by ValidateIdempotencyKey for malformed keys
+var ErrInvalidIdempotencyKey = reqctx.ErrInvalidIdempotencyKey
+
+// IdempotencyKeyConfig configures IdempotencyKeyMiddlewareWithConfig
+type IdempotencyKeyConfig struct {
+	// Required rejects requests without an Idempotency-Key header with 400
+	Required bool
+}
+
+// IdempotencyKeyMiddleware stores the Idempotency-Key header in the request context
+// Malformed keys are rejected with 400, requests without the header pass through.
+// The key is forwarded downstream by PropagatingTransport and the other propagation helpers.
+//
+// Usage:
+//
+//	payments := router.Group("/payments", httputil.IdempotencyKeyMiddleware())
+//	payments.POST("", func(c *gin.Context) {
+//		key, _ := httputil.IdempotencyKeyFromContext(c)
+//		...
+//	})
+func IdempotencyKeyMiddleware() gin.HandlerFunc {
+	return IdempotencyKeyMiddlewareWithConfig(IdempotencyKeyConfig{})
+}
+
+// IdempotencyKeyMiddlewareWithConfig is IdempotencyKeyMiddleware with a mandatory key option
+//
+// Usage:
+//
+//	router.POST("/payments", httputil.IdempotencyKeyMiddlewareWithConfig(httputil.IdempotencyKeyConfig{
+//		Required: true,
+//	}), h.CreatePayment)
+func IdempotencyKeyMiddlewareWithConfig(cfg IdempotencyKeyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if key == "" {
+			if cfg.Required {
+				RespondError(c, http.StatusBadRequest, "missing_idempotency_key", "Missing "+HeaderIdempotencyKey+" header")
+				return
+			}
+			c.Next()
+			return
+		}
+		if err := ValidateIdempotencyKey(key); err != nil {
+			RespondError(c, http.StatusBadRequest, "invalid_idempotency_key", err.Error())
+			return
+		}
+
+		c.Request = c.Request.WithContext(ContextWithIdempotencyKey(c.Request.Context(), key))
+		c.Next()
+	}
+}
+
+// ValidateIdempotencyKey checks that key is 1 to MaxIdempotencyKeyLength visible ASCII characters
+// UUIDs and opaque tokens are both accepted
+func ValidateIdempotencyKey(key string) error {
+	return reqctx.ValidateIdempotencyKey(key)
+}
+
+// ContextWithIdempotencyKey creates a new context with idempotency key value
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return reqctx.ContextWithIdempotencyKey(ctx, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key and whether it was found
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	return reqctx.IdempotencyKeyFromContext(valueContext(ctx))
+}
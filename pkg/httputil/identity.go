@@ -0,0 +1,43 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// userIDKey is the context key for authenticated user ID
+const userIDKey contextKey = "user_id"
+
+const (
+	// TenantIDKey is the public string constant for gin.Context.Set/Get of the tenant ID
+	TenantIDKey = "tenant_id"
+
+	// UserIDKey is the public string constant for gin.Context.Set/Get of the user ID
+	UserIDKey = "user_id"
+)
+
+// ContextWithTenantID creates a new context with tenant_id value
+// The tenant ID is sent downstream in X-Tenant-ID like the request ID.
+func ContextWithTenantID(ctx context.Context, tenantID string) context.Context {
+	return reqctx.ContextWithTenantID(ctx, tenantID)
+}
+
+// GetTenantIDFromContext extracts tenant_id from context.Context
+// Returns empty string if no tenant ID is set
+func GetTenantIDFromContext(ctx context.Context) string {
+	tenantID, _ := reqctx.TenantIDFromContext(ctx)
+	return tenantID
+}
+
+// ContextWithUserID creates a new context with user_id value
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// GetUserIDFromContext extracts user_id from context.Context
+// Returns empty string if no user ID is set
+func GetUserIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey).(string)
+	return userID
+}
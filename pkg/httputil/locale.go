@@ -0,0 +1,61 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+
+	"golang.org/x/text/language"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderRequestLocale carries the resolved locale of the original client as a BCP 47 tag
+// The request ID middlewares read it, or resolve it from Accept-Language at the edge, outgoing requests forward it.
+const HeaderRequestLocale = reqctx.HeaderRequestLocale
+
+// ContextWithLocale creates a new context with the request locale
+func ContextWithLocale(ctx context.Context, tag language.Tag) context.Context {
+	return reqctx.ContextWithLocale(ctx, tag)
+}
+
+// LocaleFromContext returns the request locale and whether it was found
+// A *gin.Context is accepted too.
+//
+// Usage:
+//
+//	tag, _ := httputil.LocaleFromContext(c)
+//	c.JSON(http.StatusOK, h.catalog.Render(tag, page))
+func LocaleFromContext(ctx context.Context) (language.Tag, bool) {
+	return reqctx.LocaleFromContext(valueContext(ctx))
+}
+
+// resolveLocale returns the locale of r: a valid X-Request-Locale set by an upstream service,
+// otherwise the best match of Accept-Language, otherwise cfg.DefaultLocale
+// Accept-Language is matched against cfg.SupportedLocales if set, else its preferred tag is used as is.
+func resolveLocale(r *http.Request, cfg Config) (language.Tag, bool) {
+	if tag, ok := reqctx.ParseLocale(headerGet(r.Header, HeaderRequestLocale)); ok {
+		return tag, true
+	}
+
+	if accept := headerGet(r.Header, "Accept-Language"); accept != "" {
+		if len(cfg.SupportedLocales) > 0 {
+			matcher := language.NewMatcher(cfg.SupportedLocales)
+			if tag, _, confidence := matcher.Match(parseAcceptLanguage(accept)...); confidence != language.No {
+				return tag, true
+			}
+		} else if tags := parseAcceptLanguage(accept); len(tags) > 0 && tags[0] != language.Und {
+			return tags[0], true
+		}
+	}
+
+	return cfg.DefaultLocale, cfg.DefaultLocale != language.Und
+}
+
+// parseAcceptLanguage returns the tags of an Accept-Language value ordered by preference, nil if malformed
+func parseAcceptLanguage(value string) []language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(value)
+	if err != nil {
+		return nil
+	}
+	return tags
+}
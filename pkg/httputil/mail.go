@@ -0,0 +1,23 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// MailHeaderCarrier adapts mail.Header to Carrier and Extractor, see reqctx.MailHeaderCarrier
+type MailHeaderCarrier = reqctx.MailHeaderCarrier
+
+// InjectMail stamps X-Request-ID and X-Correlation-ID on an outgoing email through carrier
+// A *gin.Context is accepted too. See reqctx.InjectMail.
+//
+// Usage:
+//
+//	msg := gomail.NewMessage()
+//	httputil.InjectMail(c, reqctx.CarrierFunc(func(key, value string) {
+//		msg.SetHeader(key, value)
+//	}))
+func InjectMail(ctx context.Context, carrier Carrier) {
+	reqctx.InjectMail(coreContext(ctx), carrier)
+}
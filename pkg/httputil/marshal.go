@@ -0,0 +1,72 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+const (
+	// ParentRequestIDKey is the MarshalContext map key for parent request ID
+	ParentRequestIDKey = "parent_request_id"
+
+	// BaggageKey is the MarshalContext map key for baggage, encoded as a W3C Baggage header value
+	BaggageKey = "baggage"
+)
+
+// MarshalContext flattens the tracing values of ctx into a string map
+// Lets systems that only speak string maps (log shippers, job queues) carry them without access
+// to the unexported context keys. Keys are RequestIDKey, CorrelationIDKey, ParentRequestIDKey,
+// TenantIDKey, UserIDKey and BaggageKey, each only if present. Nothing is generated.
+//
+// Usage:
+//
+//	job.Meta = httputil.MarshalContext(ctx)
+//
+//	// in the worker
+//	ctx := httputil.UnmarshalContext(context.Background(), job.Meta)
+func MarshalContext(ctx context.Context) map[string]string {
+	m := make(map[string]string, 6)
+
+	requestID, correlationID := idsFromContext(ctx)
+	if requestID != "" {
+		m[RequestIDKey] = requestID
+	}
+	if correlationID != "" {
+		m[CorrelationIDKey] = correlationID
+	}
+	if parentID, ok := ParentRequestIDFromContext(ctx); ok {
+		m[ParentRequestIDKey] = parentID
+	}
+	if tenantID := GetTenantIDFromContext(valueContext(ctx)); tenantID != "" {
+		m[TenantIDKey] = tenantID
+	}
+	if userID := GetUserIDFromContext(valueContext(ctx)); userID != "" {
+		m[UserIDKey] = userID
+	}
+	if baggage := reqctx.EncodeBaggage(BaggageFromContext(ctx)); baggage != "" {
+		m[BaggageKey] = baggage
+	}
+	return m
+}
+
+// UnmarshalContext stores the values of a MarshalContext map in ctx
+// IDs are validated like incoming headers, invalid ones are skipped. Missing keys leave ctx unchanged.
+func UnmarshalContext(ctx context.Context, m map[string]string) context.Context {
+	if requestID := trustedValue(m[RequestIDKey]); requestID != "" {
+		ctx = ContextWithRequestID(ctx, requestID)
+	}
+	if correlationID := trustedValue(m[CorrelationIDKey]); correlationID != "" {
+		ctx = ContextWithCorrelationID(ctx, correlationID)
+	}
+	if parentID := trustedValue(m[ParentRequestIDKey]); parentID != "" {
+		ctx = ContextWithParentRequestID(ctx, parentID)
+	}
+	if tenantID := m[TenantIDKey]; tenantID != "" {
+		ctx = ContextWithTenantID(ctx, tenantID)
+	}
+	if userID := m[UserIDKey]; userID != "" {
+		ctx = ContextWithUserID(ctx, userID)
+	}
+	return reqctx.ContextWithBaggageHeader(ctx, m[BaggageKey])
+}
@@ -0,0 +1,89 @@
+package httputil
+
+import (
+	"context"
+	"strings"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// InjectTracingToHeaders writes tracing identifiers from ctx through set
+// It works with any message header carrier (Kafka, NATS, AMQP, ...) without depending on a broker library.
+// Keys are the HTTP header names: X-Request-ID, X-Correlation-ID, and X-Parent-Request-ID,
+// Idempotency-Key, X-Tenant-ID, Baggage, X-Trace-Sampled, X-Request-Priority, X-Request-Locale, X-Client-IP,
+// X-Risk-Score, X-API-Version and X-Dry-Run if set.
+// X-Trace-Sequence and X-Request-Hops are always sent, they are the hop number and hop count of ctx plus one.
+//
+// Usage:
+//
+//	msg := &sarama.ProducerMessage{Topic: "orders", Value: value}
+//	httputil.InjectTracingToHeaders(ctx, func(key, value string) {
+//		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+//	})
+func InjectTracingToHeaders(ctx context.Context, set func(key, value string)) {
+	Inject(ctx, reqctx.CarrierFunc(set))
+}
+
+// ExtractTracingFromHeaders builds a background context from tracing identifiers read through get
+// get must return an empty string for missing keys. Like RequestIDMiddleware, a missing or invalid
+// request ID is generated and the correlation ID defaults to the request ID. The headers are
+// untrusted, see ExtractWithOptions.
+//
+// Usage:
+//
+//	ctx := httputil.ExtractTracingFromHeaders(func(key string) string {
+//		for _, h := range msg.Headers {
+//			if string(h.Key) == key {
+//				return string(h.Value)
+//			}
+//		}
+//		return ""
+//	})
+func ExtractTracingFromHeaders(get func(key string) string) context.Context {
+	return reqctx.Extract(reqctx.ExtractorFunc(get))
+}
+
+// Carrier receives tracing identifiers from Inject, see reqctx.Carrier
+type Carrier = reqctx.Carrier
+
+// Extractor provides tracing identifiers to Extract, see reqctx.Extractor
+type Extractor = reqctx.Extractor
+
+// Inject writes the tracing identifiers of ctx to carrier, a *gin.Context is accepted too
+// Every propagation helper of this package sends the same identifiers, see reqctx.Inject.
+//
+// Usage:
+//
+//	httputil.Inject(c, reqctx.HeaderCarrier(req.Header))
+func Inject(ctx context.Context, carrier Carrier) {
+	for key, value := range TracingHeadersFromContext(ctx) {
+		carrier.Set(key, value)
+	}
+}
+
+// Extract builds a background context from the tracing identifiers of carrier, see reqctx.Extract
+func Extract(carrier Extractor) context.Context {
+	return reqctx.Extract(carrier)
+}
+
+// ExtractOptions selects the carrier values honored by ExtractWithOptions, see reqctx.ExtractOptions
+type ExtractOptions = reqctx.ExtractOptions
+
+// ExtractWithOptions is Extract honoring the carrier values opts allows
+//
+// Usage:
+//
+//	// messages of a topic only internal services publish to
+//	ctx := httputil.ExtractWithOptions(carrier, httputil.ExtractOptions{Trusted: true})
+func ExtractWithOptions(carrier Extractor, opts ExtractOptions) context.Context {
+	return reqctx.ExtractContextWithOptions(context.Background(), carrier, opts)
+}
+
+// trustedValue returns the trimmed value if it passes ValidateRequestID, otherwise empty string
+func trustedValue(value string) string {
+	value = strings.TrimSpace(value)
+	if ValidateRequestID(value) != nil {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,299 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// RequestIDMiddleware establishes a stable request_id and correlation_id for every request
+// Both IDs are resolved independently, only the missing one is filled in:
+//   - X-Request-ID and X-Correlation-ID present: both are kept as sent, even if they differ
+//   - only X-Request-ID: the correlation ID is the request ID
+//   - only X-Correlation-ID: a new request ID is generated, the correlation ID is kept
+//   - neither: a new request ID is generated and used as the correlation ID too,
+//     with Config.UseAmznTraceID the request ID comes from X-Amzn-Trace-Id instead
+//
+// Incoming values are trusted only if they pass ValidateRequestID, with Config.TrustMode set to
+// AlwaysRegenerate incoming request IDs are ignored altogether, with Config.TrustedProxies they are
+// honored only from the configured reverse proxy.
+// Both are stored in gin.Context, in the request context.Context and echoed in the response headers.
+// Members of an incoming Baggage header are available via BaggageFromContext,
+// the X-Trace-Sampled decision and X-Request-Priority class of trusted requests via IsSampled and
+// PriorityFromContext, the X-Trace-Sequence hop number via SequenceFromContext, the X-Request-Hops count
+// via HopCountFromContext, the locale from X-Request-Locale or Accept-Language via LocaleFromContext,
+// the X-Dry-Run flag of trusted requests with Config.HonorDryRun via DryRunFromContext, the X-Risk-Score
+// of requests from Config.TrustedProxies via RiskScoreFromContext.
+//
+// Usage:
+//
+//	router := gin.New()
+//	router.Use(httputil.RequestIDMiddleware())
+func RequestIDMiddleware() gin.HandlerFunc {
+	return RequestIDMiddlewareWithConfig(DefaultConfig())
+}
+
+// RequestIDMiddlewareWithConfig is RequestIDMiddleware with custom header names
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddlewareWithConfig(httputil.Config{
+//		RequestIDHeader: "Request-Id",
+//	}))
+func RequestIDMiddlewareWithConfig(cfg Config) gin.HandlerFunc {
+	cfg = cfg.withDefaults()
+	cfg.ResponseHeaders = cfg.responseHeaders()
+	proxies := newTrustedProxies(cfg.TrustedProxies)
+	skip := newPathMatcher(cfg.SkipPaths)
+
+	return func(c *gin.Context) {
+		if skip.match(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		ids := resolveIDs(c.Request, cfg, proxies)
+
+		if cfg.RequestIDKeyAlias != "" {
+			c.Set(requestIDKeyAliasKey, cfg.RequestIDKeyAlias)
+		}
+		setGinRequestID(c, ids.requestID)
+		c.Set(CorrelationIDKey, ids.correlationID)
+		ctx := incomingContext(c.Request, cfg, ids, proxies)
+		if route := c.FullPath(); route != "" {
+			ctx = ContextWithRoute(ctx, route)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		writeResponseHeaders(c.Writer.Header(), cfg, ids, c.Request.Context())
+
+		if cfg.RequireCorrelationID && !ids.correlationIncoming {
+			c.AbortWithStatusJSON(http.StatusBadRequest, missingCorrelationIDBody(cfg, ids.requestID))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// requestIDs holds the IDs resolved for an incoming request
+type requestIDs struct {
+	requestID     string
+	correlationID string
+
+	// correlationIncoming reports whether the correlation ID was sent by the caller
+	correlationIncoming bool
+}
+
+// resolveIDs resolves request and correlation IDs from incoming headers, generating missing ones
+// Incoming request IDs are used only if proxies trusts r. Results are always safe to write into response headers.
+func resolveIDs(r *http.Request, cfg Config, proxies *trustedProxies) requestIDs {
+	var ids requestIDs
+	ids.correlationID = trustedHeaderValue(r, cfg.CorrelationIDHeader, cfg)
+	ids.correlationIncoming = ids.correlationID != ""
+
+	if cfg.TrustMode != AlwaysRegenerate && proxies.trusts(r) {
+		// the correlation ID never becomes the request ID, each hop keeps its own request ID
+		ids.requestID = trustedHeaderValue(r, cfg.RequestIDHeader, cfg)
+		if ids.requestID == "" && !ids.correlationIncoming && cfg.UseAmznTraceID {
+			ids.requestID = trustedValue(amznTraceRoot(headerGet(r.Header, HeaderAmznTraceID)))
+		}
+		if ids.requestID == "" && !ids.correlationIncoming && cfg.UseCloudTraceContext {
+			if trace, ok := ParseCloudTraceContext(headerGet(r.Header, HeaderCloudTraceContext)); ok {
+				ids.requestID = trace.TraceID
+			}
+		}
+	}
+	ids.requestID = headerSafeID(ids.requestID, cfg)
+
+	if ids.correlationID == "" {
+		ids.correlationID = ids.requestID
+	}
+	return ids
+}
+
+// writeResponseHeaders echoes resolved IDs and the other cfg.ResponseHeaders in the response headers
+// cfg.ResponseHeaders must be filled by cfg.responseHeaders, other values are taken from ctx.
+// Names go through http.Header.Set and are sent in canonical form whatever case the request used.
+func writeResponseHeaders(h http.Header, cfg Config, ids requestIDs, ctx context.Context) {
+	var (
+		exposed []string
+		tracing map[string]string
+	)
+	for _, name := range cfg.ResponseHeaders {
+		switch {
+		case strings.EqualFold(name, cfg.RequestIDHeader):
+			h.Set(name, ids.requestID)
+		case strings.EqualFold(name, cfg.CorrelationIDHeader):
+			h.Set(name, ids.correlationID)
+		case strings.EqualFold(name, HeaderServedBy):
+			if cfg.ServedBy != "" {
+				h.Set(HeaderServedBy, SanitizeHeaderValue(cfg.ServedBy))
+			}
+			// X-Served-By is for operators, it is never exposed to browsers
+			continue
+		case strings.EqualFold(name, HeaderAppVersion):
+			if cfg.Version == "" {
+				continue
+			}
+			h.Set(HeaderAppVersion, SanitizeHeaderValue(cfg.Version))
+		default:
+			if tracing == nil {
+				tracing = reqctx.TracingHeadersFromContext(ctx)
+			}
+			value := tracing[http.CanonicalHeaderKey(name)]
+			if value == "" {
+				continue
+			}
+			h.Set(name, SanitizeHeaderValue(value))
+		}
+		exposed = append(exposed, name)
+	}
+	if cfg.ExposeHeaders {
+		appendHeaderList(h, "Access-Control-Expose-Headers", exposed...)
+	}
+}
+
+// appendHeaderList adds names to a comma-separated header list, skipping names already present
+func appendHeaderList(h http.Header, key string, names ...string) {
+	existing := h.Values(key)
+	for _, name := range names {
+		if !headerListContains(existing, name) {
+			existing = append(existing, name)
+		}
+	}
+	h.Set(key, strings.Join(existing, ", "))
+}
+
+// headerListContains reports whether a comma-separated header list contains name case-insensitively
+func headerListContains(values []string, name string) bool {
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// missingCorrelationIDBody is the 400 response body in RequireCorrelationID mode
+func missingCorrelationIDBody(cfg Config, requestID string) map[string]string {
+	return map[string]string{
+		"error":      "Missing " + cfg.CorrelationIDHeader + " header",
+		"request_id": requestID,
+	}
+}
+
+// incomingContext builds the request context from resolved IDs and other incoming tracing headers
+// It also records the time the request was received.
+func incomingContext(r *http.Request, cfg Config, ids requestIDs, proxies *trustedProxies) context.Context {
+	// trusted gates the values a client could set to gain service it isn't entitled to
+	trusted := cfg.TrustMode != AlwaysRegenerate && proxies.trusts(r)
+
+	ctx := reqctx.ContextWithTracing(ContextWithReceivedAt(r.Context(), time.Now()), reqctx.TracingValues{
+		RequestID:       ids.requestID,
+		CorrelationID:   ids.correlationID,
+		ParentRequestID: trustedValue(firstHeaderValue(r.Header, HeaderParentRequestID)),
+	})
+	if ids.correlationIncoming {
+		ctx = contextWithCorrelationHeader(ctx, r.Header, cfg.CorrelationIDHeader)
+	}
+	if cfg.RecordStartTime {
+		ctx = ContextWithStartTime(ctx, incomingStartTime(r.Header))
+	}
+	if trusted {
+		ctx = contextWithSampledHeader(ctx, headerGet(r.Header, HeaderTraceSampled))
+		ctx = contextWithPriorityHeader(ctx, headerGet(r.Header, HeaderRequestPriority))
+	}
+	ctx = contextWithSequenceHeader(ctx, headerGet(r.Header, HeaderTraceSequence))
+	if hops, ok := reqctx.ParseHopCount(headerGet(r.Header, HeaderRequestHops)); ok {
+		ctx = ContextWithHopCount(ctx, hops)
+	}
+	if cfg.HonorDryRun && trusted && reqctx.ParseDryRun(headerGet(r.Header, HeaderDryRun)) {
+		ctx = ContextWithDryRun(ctx, true)
+	}
+	if tag, ok := resolveLocale(r, cfg); ok {
+		ctx = ContextWithLocale(ctx, tag)
+	}
+	if cfg.UseCloudTraceContext && trusted {
+		if trace, ok := ParseCloudTraceContext(headerGet(r.Header, HeaderCloudTraceContext)); ok {
+			ctx = cloudTraceValue.With(ctx, trace)
+		}
+	}
+	// unlike other values the risk score needs an explicit proxy config, nil TrustedProxies trusts everybody
+	if trusted && proxies != nil {
+		ctx = reqctx.ContextWithRiskScoreHeader(ctx, headerGet(r.Header, HeaderRiskScore))
+	}
+	if cfg.Version != "" {
+		ctx = appVersionValue.With(ctx, cfg.Version)
+	}
+	if cfg.RecordClientIP {
+		if ip := resolveClientIP(r, proxies); ip != "" {
+			ctx = ContextWithClientIP(ctx, ip)
+		}
+	}
+	ctx = reqctx.ContextWithBaggageHeader(ctx, headerGet(r.Header, HeaderBaggage))
+	if cfg.CollectChildRequestIDs {
+		ctx = ContextWithChildCollector(ctx)
+	}
+	for _, hook := range cfg.ContextHooks {
+		ctx = hook(ctx)
+	}
+	return ctx
+}
+
+// trustedHeaderValue returns the first non-empty header value if it passes validation
+func trustedHeaderValue(r *http.Request, key string, cfg Config) string {
+	value := firstHeaderValue(r.Header, key)
+	if value == "" {
+		return ""
+	}
+	if err := ValidateRequestID(value); err != nil {
+		if cfg.OnInvalidID != nil {
+			cfg.OnInvalidID(r, value, err)
+		}
+		return ""
+	}
+	return value
+}
+
+// firstHeaderValue returns the first non-empty value of a header
+// Proxies may repeat the header or merge several values into one comma-separated line
+func firstHeaderValue(h http.Header, key string) string {
+	for _, value := range headerValues(h, key) {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				return part
+			}
+		}
+	}
+	return ""
+}
+
+// headerValues returns the values of a header, matching the name case-insensitively
+// net/http canonicalizes names of parsed requests ("x-request-id" from HTTP/2 becomes "X-Request-Id"),
+// but headers built as map literals or by other frameworks may keep any case.
+func headerValues(h http.Header, key string) []string {
+	if values := h.Values(key); len(values) > 0 {
+		return values
+	}
+	for name, values := range h {
+		if strings.EqualFold(name, key) {
+			return values
+		}
+	}
+	return nil
+}
+
+// headerGet is http.Header.Get with case-insensitive name matching, see headerValues
+func headerGet(h http.Header, key string) string {
+	if values := headerValues(h, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
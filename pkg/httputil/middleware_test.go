@@ -0,0 +1,218 @@
+package httputil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serveWithMiddleware runs a request with header through RequestIDMiddlewareWithConfig(cfg)
+// It returns the IDs the handler saw and the response.
+func serveWithMiddleware(t *testing.T, cfg Config, header http.Header) (requestID, correlationID string, w *httptest.ResponseRecorder) {
+	t.Helper()
+
+	router := gin.New()
+	router.Use(RequestIDMiddlewareWithConfig(cfg))
+	router.GET("/", func(c *gin.Context) {
+		requestID = GetRequestIDFromContext(c.Request.Context())
+		correlationID = GetCorrelationIDFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return requestID, correlationID, w
+}
+
+func TestRequestIDMiddlewareIDPresence(t *testing.T) {
+	tests := []struct {
+		name              string
+		header            http.Header
+		wantRequestID     string // empty means generated
+		wantCorrelationID string // empty means the request ID
+	}{
+		{
+			name:              "both present and different",
+			header:            http.Header{"X-Request-Id": {"req-1"}, "X-Correlation-Id": {"corr-1"}},
+			wantRequestID:     "req-1",
+			wantCorrelationID: "corr-1",
+		},
+		{
+			name:          "only request ID",
+			header:        http.Header{"X-Request-Id": {"req-1"}},
+			wantRequestID: "req-1",
+		},
+		{
+			name:              "only correlation ID",
+			header:            http.Header{"X-Correlation-Id": {"corr-1"}},
+			wantCorrelationID: "corr-1",
+		},
+		{
+			name:   "neither",
+			header: http.Header{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestID, correlationID, w := serveWithMiddleware(t, DefaultConfig(), tt.header)
+
+			switch {
+			case tt.wantRequestID != "" && requestID != tt.wantRequestID:
+				t.Errorf("request ID = %q, want %q", requestID, tt.wantRequestID)
+			case tt.wantRequestID == "" && (ValidateRequestID(requestID) != nil || requestID == "corr-1"):
+				t.Errorf("request ID = %q, want a generated one", requestID)
+			}
+
+			wantCorrelationID := tt.wantCorrelationID
+			if wantCorrelationID == "" {
+				wantCorrelationID = requestID
+			}
+			if correlationID != wantCorrelationID {
+				t.Errorf("correlation ID = %q, want %q", correlationID, wantCorrelationID)
+			}
+
+			if got := w.Header().Get(HeaderRequestID); got != requestID {
+				t.Errorf("response %s = %q, want %q", HeaderRequestID, got, requestID)
+			}
+			if got := w.Header().Get(HeaderCorrelationID); got != wantCorrelationID {
+				t.Errorf("response %s = %q, want %q", HeaderCorrelationID, got, wantCorrelationID)
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddlewareHeaderCase(t *testing.T) {
+	for _, name := range []string{"x-request-id", "X-REQUEST-ID", "X-Request-Id", "X-Request-ID", "x-ReQuEsT-iD"} {
+		t.Run(name, func(t *testing.T) {
+			requestID, correlationID, w := serveWithMiddleware(t, DefaultConfig(), http.Header{
+				name:               {"req-1"},
+				"x-correlation-id": {"corr-1"},
+			})
+			if requestID != "req-1" {
+				t.Errorf("request ID = %q, want req-1", requestID)
+			}
+			if correlationID != "corr-1" {
+				t.Errorf("correlation ID = %q, want corr-1", correlationID)
+			}
+
+			if got := w.Header()["X-Request-Id"]; len(got) != 1 || got[0] != "req-1" {
+				t.Errorf("canonical response header = %v, want [req-1]", got)
+			}
+			for key := range w.Header() {
+				if key != http.CanonicalHeaderKey(key) {
+					t.Errorf("response header %q is not canonical", key)
+				}
+			}
+		})
+	}
+}
+
+// serveContext runs req through RequestIDMiddlewareWithConfig(cfg) and returns the request context the handler saw
+func serveContext(t *testing.T, cfg Config, req *http.Request) context.Context {
+	t.Helper()
+
+	var ctx context.Context
+	router := gin.New()
+	router.Use(RequestIDMiddlewareWithConfig(cfg))
+	router.GET("/", func(c *gin.Context) {
+		ctx = c.Request.Context()
+	})
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	return ctx
+}
+
+// trustTests are the trust configurations of a header honored only from trusted proxies
+var trustTests = []struct {
+	name        string
+	cfg         Config
+	remoteAddr  string
+	wantTrusted bool
+}{
+	{name: "default", wantTrusted: true},
+	{name: "AlwaysRegenerate", cfg: Config{TrustMode: AlwaysRegenerate}, wantTrusted: false},
+	{name: "trusted proxy", cfg: Config{TrustedProxies: &TrustedProxyConfig{CIDRs: []string{"10.0.0.0/8"}}}, remoteAddr: "10.1.2.3:4567", wantTrusted: true},
+	{name: "untrusted peer", cfg: Config{TrustedProxies: &TrustedProxyConfig{CIDRs: []string{"10.0.0.0/8"}}}, remoteAddr: "203.0.113.7:4567", wantTrusted: false},
+}
+
+func TestRequestIDMiddlewarePriorityTrust(t *testing.T) {
+	for _, tt := range trustTests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.remoteAddr != "" {
+				req.RemoteAddr = tt.remoteAddr
+			}
+			req.Header.Set(HeaderRequestPriority, "high")
+
+			want := PriorityNormal
+			if tt.wantTrusted {
+				want = PriorityHigh
+			}
+			if got := PriorityFromContext(serveContext(t, tt.cfg, req)); got != want {
+				t.Errorf("priority = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddlewareDryRunTrust(t *testing.T) {
+	for _, tt := range trustTests {
+		for _, honor := range []bool{false, true} {
+			cfg := tt.cfg
+			cfg.HonorDryRun = honor
+			t.Run(fmt.Sprintf("%s/HonorDryRun=%v", tt.name, honor), func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				if tt.remoteAddr != "" {
+					req.RemoteAddr = tt.remoteAddr
+				}
+				req.Header.Set(HeaderDryRun, "true")
+
+				if got, want := DryRunFromContext(serveContext(t, cfg, req)), honor && tt.wantTrusted; got != want {
+					t.Errorf("dry run = %v, want %v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestRequestIDMiddlewareRiskScoreTrust(t *testing.T) {
+	for _, tt := range trustTests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.remoteAddr != "" {
+				req.RemoteAddr = tt.remoteAddr
+			}
+			req.Header.Set(HeaderRiskScore, "0.1")
+
+			// the default config has no TrustedProxies and must not honor the score
+			want := tt.wantTrusted && tt.cfg.TrustedProxies != nil
+			if _, got := RiskScoreFromContext(serveContext(t, tt.cfg, req)); got != want {
+				t.Errorf("risk score found = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddlewareSampledTrust(t *testing.T) {
+	for _, tt := range trustTests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.remoteAddr != "" {
+				req.RemoteAddr = tt.remoteAddr
+			}
+			req.Header.Set(HeaderTraceSampled, "0")
+
+			// requests without an honored decision are sampled
+			if got, want := IsSampled(serveContext(t, tt.cfg, req)), !tt.wantTrusted; got != want {
+				t.Errorf("sampled = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// LogKeyUploadPart is the log attribute key for the multipart upload part of PartContext
+const LogKeyUploadPart = "upload_part"
+
+// uploadPartValue holds the name of the upload part being processed
+var uploadPartValue = reqctx.NewContextValue[string]("upload_part")
+
+// PartContext returns a detached context for processing one part of a multipart upload in its own goroutine
+// It is DetachContext(c) plus the part name, so part logs carry request_id, correlation_id and
+// upload_part (LoggerFromContext, ContextHandler). Reading c.Request.Context() from a part goroutine
+// is the usual way the trace gets lost mid-stream: the request context dies with the handler, and
+// gin.Context is reused after it. Parts keep running if the client disconnects, stop them explicitly.
+//
+// Usage:
+//
+//	reader, err := c.Request.MultipartReader()
+//	if err != nil {
+//		httputil.RespondError(c, http.StatusBadRequest, "invalid_upload", err.Error())
+//		return
+//	}
+//	var g errgroup.Group
+//	for {
+//		part, err := reader.NextPart()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			httputil.RespondError(c, http.StatusBadRequest, "invalid_upload", err.Error())
+//			return
+//		}
+//		path, err := h.spool(part) // parts must be read in order, process the spooled copy concurrently
+//		if err != nil {
+//			httputil.RespondWithError(c, err)
+//			return
+//		}
+//		ctx := httputil.PartContext(c, part.FileName())
+//		g.Go(func() error {
+//			httputil.LoggerFromContext(ctx, h.log).Info("processing part")
+//			return h.storage.Import(ctx, path)
+//		})
+//	}
+//	if err := g.Wait(); err != nil {
+//		httputil.RespondWithError(c, err)
+//		return
+//	}
+func PartContext(c *gin.Context, part string) context.Context {
+	return uploadPartValue.With(DetachContext(c), part)
+}
+
+// UploadPartFromContext returns the upload part name stored by PartContext and whether it was found
+func UploadPartFromContext(ctx context.Context) (string, bool) {
+	return uploadPartValue.Get(valueContext(ctx))
+}
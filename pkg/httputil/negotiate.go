@@ -0,0 +1,104 @@
+package httputil
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorPageData is the data of the HTML error page rendered by RespondErrorNegotiated
+type ErrorPageData struct {
+	Status    int
+	Code      string
+	Message   string
+	RequestID string
+}
+
+// defaultErrorPage is the built-in HTML error page, see SetErrorPageTemplate
+var defaultErrorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{ .Status }} {{ .Message }}</title></head>
+<body>
+<h1>{{ .Status }}</h1>
+<p>{{ .Message }}</p>
+<p><small>Request ID: <code>{{ .RequestID }}</code></small></p>
+</body>
+</html>
+`))
+
+var errorPage atomic.Pointer[template.Template]
+
+// SetErrorPageTemplate replaces the HTML error page of RespondErrorNegotiated, nil restores the default
+// The template is executed with ErrorPageData. Safe for concurrent use.
+//
+// Usage:
+//
+//	httputil.SetErrorPageTemplate(template.Must(template.ParseFiles("templates/error.html")))
+func SetErrorPageTemplate(tmpl *template.Template) {
+	errorPage.Store(tmpl)
+}
+
+// RespondErrorNegotiated aborts the request with an error in the format the Accept header asks for
+// JSON (the default and for clients without Accept) is the ErrorResponse of RespondError,
+// HTML is the SetErrorPageTemplate page and text/plain or anything else is a short text,
+// all carrying the request_id. Status, code and message come from RegisterErrorStatus if err matches
+// a mapping, otherwise they are derived from status with the http.StatusText message. The error
+// text itself is never exposed.
+//
+// Usage:
+//
+//	if err != nil {
+//		httputil.RespondErrorNegotiated(c, http.StatusInternalServerError, err)
+//		return
+//	}
+func RespondErrorNegotiated(c *gin.Context, status int, err error) {
+	code, message := negotiatedError(status)
+	if m, ok := lookupErrorMapping(err); ok {
+		status, code, message = m.status, m.code, m.message
+	}
+
+	requestID := GetRequestID(c)
+	c.Header(HeaderRequestID, SanitizeHeaderValue(requestID))
+
+	switch c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML, gin.MIMEPlain) {
+	case gin.MIMEJSON:
+		c.AbortWithStatusJSON(status, ErrorResponse{
+			Error:     ErrorBody{Code: code, Message: message},
+			RequestID: requestID,
+		})
+	case gin.MIMEHTML:
+		tmpl := errorPage.Load()
+		if tmpl == nil {
+			tmpl = defaultErrorPage
+		}
+		var buf bytes.Buffer
+		data := ErrorPageData{Status: status, Code: code, Message: message, RequestID: requestID}
+		if tmpl.Execute(&buf, data) == nil {
+			c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+			c.Abort()
+			return
+		}
+		respondPlainError(c, status, message, requestID)
+	default:
+		respondPlainError(c, status, message, requestID)
+	}
+}
+
+// respondPlainError aborts with a text/plain error carrying the request ID
+func respondPlainError(c *gin.Context, status int, message, requestID string) {
+	c.Data(status, "text/plain; charset=utf-8", []byte(message+"\nrequest_id: "+requestID+"\n"))
+	c.Abort()
+}
+
+// negotiatedError derives code and message of an unmapped error answered with status
+func negotiatedError(status int) (code, message string) {
+	text := http.StatusText(status)
+	if text == "" {
+		text = "Error"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_"), text
+}
@@ -0,0 +1,56 @@
+package httputil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRespondErrorNegotiated(t *testing.T) {
+	withErrorMappings(t)
+	errNotFound := errors.New("order not found")
+	RegisterErrorStatus(errNotFound, http.StatusNotFound, "order_not_found", "Order not found")
+	secret := "db-primary.internal:5432"
+
+	tests := []struct {
+		name        string
+		status      int
+		err         error
+		accept      string
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "mapped JSON", status: http.StatusInternalServerError, err: fmt.Errorf("%s: %w", secret, errNotFound), wantStatus: http.StatusNotFound, wantMessage: "Order not found"},
+		{name: "mapped HTML", status: http.StatusInternalServerError, err: fmt.Errorf("%s: %w", secret, errNotFound), accept: gin.MIMEHTML, wantStatus: http.StatusNotFound, wantMessage: "Order not found"},
+		{name: "unmapped 4xx JSON", status: http.StatusBadRequest, err: errors.New(secret), wantStatus: http.StatusBadRequest, wantMessage: "Bad Request"},
+		{name: "unmapped 4xx text", status: http.StatusBadRequest, err: errors.New(secret), accept: gin.MIMEPlain, wantStatus: http.StatusBadRequest, wantMessage: "Bad Request"},
+		{name: "unmapped 5xx HTML", status: http.StatusBadGateway, err: errors.New(secret), accept: gin.MIMEHTML, wantStatus: http.StatusBadGateway, wantMessage: "Bad Gateway"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				c.Request.Header.Set("Accept", tt.accept)
+			}
+			RespondErrorNegotiated(c, tt.status, tt.err)
+
+			body := w.Body.String()
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if !strings.Contains(body, tt.wantMessage) {
+				t.Errorf("body %q lacks %q", body, tt.wantMessage)
+			}
+			if strings.Contains(body, secret) {
+				t.Errorf("body %q leaks the error text", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package httputil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrMiddlewareOrder is returned by ValidateMiddlewareOrder for a misordered middleware chain
+var ErrMiddlewareOrder = errors.New("httputil: invalid middleware order")
+
+// middlewareRole is the part a recognized middleware plays in the tracing stack
+type middlewareRole int
+
+const (
+	roleUnknown middlewareRole = iota
+	roleRequestID
+	roleRecovery
+	roleLogging
+)
+
+// middlewareRoles maps constructor names to roles, middlewares are recognized by their closure names
+var middlewareRoles = map[string]middlewareRole{
+	funcName(RequestIDMiddlewareWithConfig):  roleRequestID,
+	funcName(RecoveryMiddlewareWithReporter): roleRecovery,
+	funcName(gin.CustomRecoveryWithWriter):   roleRecovery,
+	funcName(AccessLogMiddlewareWithConfig):  roleLogging,
+	funcName(gin.LoggerWithConfig):           roleLogging,
+}
+
+// ValidateMiddlewareOrder checks a middleware chain for orderings that lose traces
+// The request ID middleware must be first, so every log line below it carries the request_id,
+// and recovery must come before logging middlewares, so panics are logged. Recognized middlewares
+// are the ones of this package plus gin.Logger and gin.Recovery, others are ignored.
+// Call it at startup to fail fast instead of finding out during an outage.
+//
+// Usage:
+//
+//	handlers := []gin.HandlerFunc{
+//		httputil.RequestIDMiddleware(),
+//		httputil.RecoveryMiddleware(logger),
+//		httputil.AccessLogMiddleware(logger),
+//	}
+//	if err := httputil.ValidateMiddlewareOrder(handlers); err != nil {
+//		log.Fatal(err)
+//	}
+//	router.Use(handlers...)
+func ValidateMiddlewareOrder(handlers []gin.HandlerFunc) error {
+	first := map[middlewareRole]int{}
+	for i, h := range handlers {
+		role := roleOf(h)
+		if _, seen := first[role]; !seen {
+			first[role] = i
+		}
+	}
+
+	requestID, ok := first[roleRequestID]
+	if !ok {
+		return fmt.Errorf("%w: request ID middleware is missing, it must be first", ErrMiddlewareOrder)
+	}
+	if requestID != 0 {
+		return fmt.Errorf("%w: request ID middleware is handlers[%d], it must be first", ErrMiddlewareOrder, requestID)
+	}
+
+	recovery, hasRecovery := first[roleRecovery]
+	logging, hasLogging := first[roleLogging]
+	if hasRecovery && hasLogging && recovery > logging {
+		return fmt.Errorf("%w: recovery middleware handlers[%d] must come before logging middleware handlers[%d]",
+			ErrMiddlewareOrder, recovery, logging)
+	}
+	return nil
+}
+
+// roleOf returns the role of h by matching its closure name against middlewareRoles
+func roleOf(h gin.HandlerFunc) middlewareRole {
+	name := funcName(h)
+	if i := strings.LastIndex(name, ".func"); i >= 0 {
+		name = name[:i]
+	}
+	return middlewareRoles[name]
+}
+
+// funcName returns the fully qualified name of a function value
+func funcName(fn any) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return ""
+	}
+	return f.Name()
+}
@@ -0,0 +1,72 @@
+package httputil
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// PropagationPolicy selects which tracing headers are sent to a destination
+type PropagationPolicy int
+
+const (
+	// PropagateInternal sends all tracing headers: IDs, parent, idempotency key and baggage
+	PropagateInternal PropagationPolicy = iota
+
+	// PropagateExternal sends only X-Request-ID, so third parties can quote it in support
+	// requests without learning anything else about our internals
+	PropagateExternal
+)
+
+// PropagateTo adds the tracing headers from req.Context() allowed by policy
+//
+// Usage:
+//
+//	req, _ := http.NewRequestWithContext(ctx, "POST", "https://api.stripe.com/v1/charges", body)
+//	httputil.PropagateTo(req, httputil.PropagateExternal)
+func PropagateTo(req *http.Request, policy PropagationPolicy) {
+	cfg := DefaultConfig()
+	for key, value := range policyHeaders(outgoingHeaders(req.Context(), cfg), cfg, policy) {
+		req.Header.Set(key, value)
+	}
+}
+
+// PolicyForHost returns PropagateInternal if host matches one of internalHosts, PropagateExternal otherwise
+// A pattern "*.internal.example.com" matches any subdomain of internal.example.com, other patterns match
+// exactly. Matching ignores case and the port of host.
+//
+// Usage:
+//
+//	httputil.PropagateTo(req, httputil.PolicyForHost(req.URL.Host, "*.internal.example.com"))
+func PolicyForHost(host string, internalHosts ...string) PropagationPolicy {
+	host = strings.ToLower(hostname(host))
+	for _, pattern := range internalHosts {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return PropagateInternal
+			}
+			continue
+		}
+		if host == pattern {
+			return PropagateInternal
+		}
+	}
+	return PropagateExternal
+}
+
+// policyHeaders drops the headers policy doesn't allow
+func policyHeaders(headers map[string]string, cfg Config, policy PropagationPolicy) map[string]string {
+	if policy == PropagateInternal {
+		return headers
+	}
+	return map[string]string{cfg.RequestIDHeader: headers[cfg.RequestIDHeader]}
+}
+
+// hostname strips the port from a host[:port] value
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return strings.Trim(host, "[]")
+}
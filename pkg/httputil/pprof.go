@@ -0,0 +1,29 @@
+package httputil
+
+import (
+	"context"
+	"runtime/pprof"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileLabelRequestID is the pprof label carrying the request ID
+const ProfileLabelRequestID = "request_id"
+
+// ProfileLabelsMiddleware runs the rest of the chain under pprof.Do with a request_id label
+// CPU and goroutine profiles can then be filtered by request, e.g. go tool pprof -tagfocus=request_id=<id>.
+// Goroutines started with the request context inherit the label. Labels cost a small allocation
+// per request, so the middleware is opt-in, see WithProfileLabels. Mount it after RequestIDMiddleware.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.ProfileLabelsMiddleware())
+func ProfileLabelsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		labels := pprof.Labels(ProfileLabelRequestID, GetRequestID(c))
+		pprof.Do(c.Request.Context(), labels, func(ctx context.Context) {
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderRequestPriority carries the request priority class for downstream load shedding
+// The request ID middlewares read it from trusted requests only (see Config.TrustedProxies), so
+// clients can't promote their own traffic, and ignore it with TrustMode AlwaysRegenerate.
+// Outgoing requests forward it.
+const HeaderRequestPriority = reqctx.HeaderRequestPriority
+
+// Priority is the QoS class of a request, see reqctx.Priority
+type Priority = reqctx.Priority
+
+const (
+	// PriorityLow marks batch and background traffic, shed first under load
+	PriorityLow = reqctx.PriorityLow
+
+	// PriorityNormal is the default for requests without a priority
+	PriorityNormal = reqctx.PriorityNormal
+
+	// PriorityHigh marks interactive user traffic, shed last
+	PriorityHigh = reqctx.PriorityHigh
+)
+
+// ContextWithPriority creates a new context with the request priority
+func ContextWithPriority(ctx context.Context, p Priority) context.Context {
+	return reqctx.ContextWithPriority(ctx, p)
+}
+
+// PriorityFromContext returns the request priority, PriorityNormal if none is set
+// A *gin.Context is accepted too.
+//
+// Usage:
+//
+//	if httputil.PriorityFromContext(c) == httputil.PriorityLow && shedder.Overloaded() {
+//		httputil.RespondError(c, http.StatusServiceUnavailable, "overloaded", "try again later")
+//		return
+//	}
+func PriorityFromContext(ctx context.Context) Priority {
+	return reqctx.PriorityFromContext(valueContext(ctx))
+}
+
+// contextWithPriorityHeader stores a valid X-Request-Priority value in ctx, unknown values are ignored
+func contextWithPriorityHeader(ctx context.Context, value string) context.Context {
+	if p, ok := reqctx.ParsePriority(value); ok {
+		return ContextWithPriority(ctx, p)
+	}
+	return ctx
+}
@@ -0,0 +1,40 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// ContextKey names a request-scoped value ProjectContext can copy, see reqctx.ContextKey
+type ContextKey = reqctx.ContextKey
+
+// Context keys accepted by ProjectContext, each names the value of the matching ContextWith* function
+const (
+	ContextKeyRequestID       = reqctx.ContextKeyRequestID
+	ContextKeyCorrelationID   = reqctx.ContextKeyCorrelationID
+	ContextKeyParentRequestID = reqctx.ContextKeyParentRequestID
+	ContextKeyIdempotencyKey  = reqctx.ContextKeyIdempotencyKey
+	ContextKeyTenantID        = reqctx.ContextKeyTenantID
+	ContextKeyBaggage         = reqctx.ContextKeyBaggage
+	ContextKeySampled         = reqctx.ContextKeySampled
+	ContextKeyPriority        = reqctx.ContextKeyPriority
+	ContextKeyLocale          = reqctx.ContextKeyLocale
+	ContextKeyClientIP        = reqctx.ContextKeyClientIP
+	ContextKeyRiskScore       = reqctx.ContextKeyRiskScore
+	ContextKeyAPIVersion      = reqctx.ContextKeyAPIVersion
+	ContextKeyDryRun          = reqctx.ContextKeyDryRun
+	ContextKeySequence        = reqctx.ContextKeySequence
+	ContextKeyHopCount        = reqctx.ContextKeyHopCount
+)
+
+// ProjectContext returns a new background context holding only the listed values of ctx
+// See reqctx.ProjectContext. A *gin.Context is accepted too.
+//
+// Usage:
+//
+//	ctx := httputil.ProjectContext(c, httputil.ContextKeyRequestID, httputil.ContextKeyCorrelationID)
+//	partner.Notify(ctx, event)
+func ProjectContext(ctx context.Context, keys ...ContextKey) context.Context {
+	return reqctx.ProjectContext(valueContext(ctx), keys...)
+}
@@ -0,0 +1,56 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+)
+
+// Propagator stamps tracing headers computed once onto many outgoing requests
+// It carries the same headers as PropagateRequestIDFromContext. A Propagator is immutable
+// and safe for concurrent use.
+type Propagator struct {
+	// header holds canonical header names, so Apply skips canonicalization
+	header http.Header
+}
+
+// PropagatorFromContext precomputes the tracing headers of ctx for batch requests
+// Context lookups, baggage encoding and the fallback ID generation happen once here instead of
+// per request. If ctx has no request ID, every request stamped by the Propagator gets the same generated one.
+//
+// Usage:
+//
+//	p := httputil.PropagatorFromContext(ctx)
+//	for _, item := range items {
+//		req, _ := http.NewRequestWithContext(ctx, "POST", url, item.Body())
+//		p.Apply(req)
+//		resp, err := client.Do(req)
+//		// ...
+//	}
+func PropagatorFromContext(ctx context.Context) *Propagator {
+	headers := outgoingHeaders(ctx, DefaultConfig())
+	header := make(http.Header, len(headers))
+	for key, value := range headers {
+		header.Set(key, value)
+	}
+	return &Propagator{header: header}
+}
+
+// Apply sets the precomputed tracing headers on req, replacing values already present
+func (p *Propagator) Apply(req *http.Request) {
+	if req.Header == nil {
+		req.Header = make(http.Header, len(p.header))
+	}
+	for key, values := range p.header {
+		// a fresh slice per request, so editing one request's headers can't leak into others
+		req.Header[key] = []string{values[0]}
+	}
+}
+
+// Headers returns a copy of the precomputed headers as a map, like TracingHeadersFromContext
+func (p *Propagator) Headers() map[string]string {
+	headers := make(map[string]string, len(p.header))
+	for key, values := range p.header {
+		headers[key] = values[0]
+	}
+	return headers
+}
@@ -0,0 +1,81 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestPropagator(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{name: "stored IDs", ctx: ContextWithBaggage(ContextWithCorrelationID(ContextWithRequestID(context.Background(), "req-1"), "corr-1"), "region", "eu")},
+		{name: "no IDs", ctx: context.Background()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PropagatorFromContext(tt.ctx)
+			want := http.Header{}
+			for key, value := range p.Headers() {
+				want.Set(key, value)
+			}
+			if want.Get(HeaderRequestID) == "" {
+				t.Fatalf("no %s in %v", HeaderRequestID, want)
+			}
+
+			var reqs []*http.Request
+			for i := 0; i < 3; i++ {
+				req, _ := http.NewRequest(http.MethodGet, "http://orders.internal/", nil)
+				req.Header.Set(HeaderRequestID, "stale")
+				p.Apply(req)
+				reqs = append(reqs, req)
+			}
+			reqs[0].Header.Set(HeaderCorrelationID, "edited")
+
+			for i, req := range reqs[1:] {
+				for key := range want {
+					if got := req.Header.Values(key); len(got) != 1 || got[0] != want.Get(key) {
+						t.Errorf("request %d: %s = %v, want [%s]", i+1, key, got, want.Get(key))
+					}
+				}
+			}
+			if tt.ctx == context.Background() {
+				return
+			}
+			for key, value := range TracingHeadersFromContext(tt.ctx) {
+				if want.Get(key) != value {
+					t.Errorf("%s = %q, want %q as PropagateRequestIDFromContext sends", key, want.Get(key), value)
+				}
+			}
+		})
+	}
+}
+
+func TestPropagatorNilHeader(t *testing.T) {
+	req := &http.Request{}
+	PropagatorFromContext(ContextWithRequestID(context.Background(), "req-1")).Apply(req)
+	if got := req.Header.Get(HeaderRequestID); got != "req-1" {
+		t.Errorf("%s = %q, want req-1", HeaderRequestID, got)
+	}
+}
+
+func BenchmarkPropagateRequestIDFromContext(b *testing.B) {
+	ctx := ContextWithBaggage(ContextWithCorrelationID(ContextWithRequestID(context.Background(), "req-1"), "corr-1"), "region", "eu")
+	req, _ := http.NewRequest(http.MethodGet, "http://orders.internal/", nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PropagateRequestIDFromContext(ctx, req)
+	}
+}
+
+func BenchmarkPropagatorApply(b *testing.B) {
+	ctx := ContextWithBaggage(ContextWithCorrelationID(ContextWithRequestID(context.Background(), "req-1"), "corr-1"), "region", "eu")
+	req, _ := http.NewRequest(http.MethodGet, "http://orders.internal/", nil)
+	p := PropagatorFromContext(ctx)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p.Apply(req)
+	}
+}
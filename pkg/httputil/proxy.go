@@ -0,0 +1,118 @@
+package httputil
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// TrustedProxyConfig restricts trust in incoming request IDs to requests from a reverse proxy
+// A request comes from the proxy if its source IP is in CIDRs or it carries SecretHeader with Secret.
+// Request IDs of other requests are regenerated like with AlwaysRegenerate.
+type TrustedProxyConfig struct {
+	// CIDRs lists proxy networks like "10.0.0.0/8", a bare IP is a single address
+	// The source IP is the TCP peer address, the same as gin's c.RemoteIP().
+	CIDRs []string
+
+	// SecretHeader is a header the proxy sets to Secret, e.g. "X-Proxy-Secret"
+	// Both must be set for the check to apply. Configure the proxy to overwrite the header on every request.
+	SecretHeader string
+
+	// Secret is the shared secret value of SecretHeader
+	Secret string
+}
+
+// TrustCheck returns the trust decision the request ID middlewares take for requests under cfg
+// It is false with TrustMode AlwaysRegenerate and for peers outside cfg.TrustedProxies, a nil
+// TrustedProxies trusts every request. An invalid CIDR panics like in the middlewares.
+//
+// Usage:
+//
+//	trusted := httputil.TrustCheck(cfg)
+//	if trusted(r) {
+//		ctx = httputil.ContextWithPriority(ctx, priority)
+//	}
+func TrustCheck(cfg Config) func(r *http.Request) bool {
+	proxies := newTrustedProxies(cfg.TrustedProxies)
+	return func(r *http.Request) bool {
+		return cfg.TrustMode != AlwaysRegenerate && proxies.trusts(r)
+	}
+}
+
+// trustedProxies is a TrustedProxyConfig with parsed networks
+type trustedProxies struct {
+	prefixes     []netip.Prefix
+	secretHeader string
+	secret       string
+}
+
+// newTrustedProxies parses cfg, panicking on an invalid CIDR so misconfiguration fails at startup
+func newTrustedProxies(cfg *TrustedProxyConfig) *trustedProxies {
+	if cfg == nil {
+		return nil
+	}
+
+	proxies := &trustedProxies{secretHeader: cfg.SecretHeader, secret: cfg.Secret}
+	for _, cidr := range cfg.CIDRs {
+		prefix, err := parsePrefix(cidr)
+		if err != nil {
+			panic("httputil: invalid trusted proxy CIDR " + cidr + ": " + err.Error())
+		}
+		proxies.prefixes = append(proxies.prefixes, prefix)
+	}
+	return proxies
+}
+
+// parsePrefix parses a CIDR or a bare IP address
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// trusts reports whether r came through a trusted proxy, a nil receiver trusts everybody
+func (p *trustedProxies) trusts(r *http.Request) bool {
+	if p == nil {
+		return true
+	}
+	if p.secretHeader != "" && p.secret != "" {
+		got := headerGet(r.Header, p.secretHeader)
+		if subtle.ConstantTimeCompare([]byte(got), []byte(p.secret)) == 1 {
+			return true
+		}
+	}
+
+	addr, ok := remoteAddr(r)
+	return ok && p.containsAddr(addr)
+}
+
+// containsAddr reports whether addr is in one of the trusted CIDRs
+func (p *trustedProxies) containsAddr(addr netip.Addr) bool {
+	for _, prefix := range p.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteAddr returns the TCP peer address of r, like gin's c.RemoteIP()
+func remoteAddr(r *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
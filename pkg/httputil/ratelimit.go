@@ -0,0 +1,79 @@
+package httputil
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitTTL is how long the limiter of an idle client is kept
+const rateLimitTTL = 10 * time.Minute
+
+// clientLimiter is the token bucket of one client with the time it was last used
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimitMiddleware limits every client IP (c.ClientIP()) to rps requests per second with bursts of burst
+// Rejected requests get 429 with an ErrorResponse carrying the request_id, so throttled clients
+// can report it, and a Retry-After header. Limiters of clients idle for 10 minutes are evicted
+// to bound memory. Configure gin trusted proxies so c.ClientIP() is the real client behind a balancer.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.RateLimitMiddleware(10, 20))
+//
+// Response:
+//
+//	{"error": {"code": "rate_limited", "message": "Too many requests"}, "request_id": "..."}
+func RateLimitMiddleware(rps float64, burst int) gin.HandlerFunc {
+	var (
+		mu        sync.Mutex
+		limiters  = make(map[string]*clientLimiter)
+		lastSweep = time.Now()
+	)
+
+	limiterFor := func(key string, now time.Time) *rate.Limiter {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if now.Sub(lastSweep) > rateLimitTTL {
+			for k, l := range limiters {
+				if now.Sub(l.lastSeen) > rateLimitTTL {
+					delete(limiters, k)
+				}
+			}
+			lastSweep = now
+		}
+
+		l, ok := limiters[key]
+		if !ok {
+			l = &clientLimiter{limiter: rate.NewLimiter(rate.Limit(rps), burst)}
+			limiters[key] = l
+		}
+		l.lastSeen = now
+		return l.limiter
+	}
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		reservation := limiterFor(c.ClientIP(), now).ReserveN(now, 1)
+		if !reservation.OK() {
+			RespondError(c, http.StatusTooManyRequests, "rate_limited", "Too many requests")
+			return
+		}
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			reservation.CancelAt(now)
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			RespondError(c, http.StatusTooManyRequests, "rate_limited", "Too many requests")
+			return
+		}
+		c.Next()
+	}
+}
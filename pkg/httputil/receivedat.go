@@ -0,0 +1,29 @@
+package httputil
+
+import (
+	"context"
+	"time"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// receivedAtValue holds the time this service received the request
+var receivedAtValue = reqctx.NewContextValue[time.Time]("received_at")
+
+// ContextWithReceivedAt creates a new context with the time the request was received
+func ContextWithReceivedAt(ctx context.Context, t time.Time) context.Context {
+	return receivedAtValue.With(ctx, t)
+}
+
+// ReceivedAtFromContext returns the time this service received the request and whether it was found
+// The request ID middlewares record it on every request. Unlike StartTimeFromContext it is local to
+// this hop, is never propagated and keeps the monotonic clock reading, so time.Since is precise.
+//
+// Usage:
+//
+//	if receivedAt, ok := httputil.ReceivedAtFromContext(ctx); ok {
+//		log.Info("payment authorized", "since_received", time.Since(receivedAt))
+//	}
+func ReceivedAtFromContext(ctx context.Context) (time.Time, bool) {
+	return receivedAtValue.Get(valueContext(ctx))
+}
@@ -0,0 +1,77 @@
+package httputil
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// errHijackNotSupported is returned by Hijack when the underlying writer can't be hijacked
+var errHijackNotSupported = errors.New("httputil: underlying ResponseWriter does not implement http.Hijacker")
+
+// ResponseRecorder wraps http.ResponseWriter and records status code and bytes written
+// http.Flusher and http.Hijacker are delegated to the wrapped writer when it supports them
+type ResponseRecorder struct {
+	http.ResponseWriter
+
+	// StatusCode is the status sent to the client, 0 until the header is written
+	StatusCode int
+
+	// BytesWritten is the number of body bytes written
+	BytesWritten int64
+}
+
+// NewResponseRecorder wraps w for status and size recording
+//
+// Usage:
+//
+//	rec := httputil.NewResponseRecorder(w)
+//	next.ServeHTTP(rec, r)
+//	log.Info("request", "status", rec.StatusCode, "bytes", rec.BytesWritten,
+//		"request_id", httputil.GetRequestIDFromContext(r.Context()))
+func NewResponseRecorder(w http.ResponseWriter) *ResponseRecorder {
+	return &ResponseRecorder{ResponseWriter: w}
+}
+
+// WriteHeader implements http.ResponseWriter
+func (r *ResponseRecorder) WriteHeader(statusCode int) {
+	if r.StatusCode == 0 {
+		r.StatusCode = statusCode
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write implements http.ResponseWriter, the status becomes 200 if WriteHeader wasn't called
+func (r *ResponseRecorder) Write(b []byte) (int, error) {
+	if r.StatusCode == 0 {
+		r.StatusCode = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.BytesWritten += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher, it is a no-op if the wrapped writer can't flush
+func (r *ResponseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		if r.StatusCode == 0 {
+			r.StatusCode = http.StatusOK
+		}
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker
+func (r *ResponseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackNotSupported
+	}
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (r *ResponseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
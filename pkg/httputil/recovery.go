@@ -0,0 +1,166 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorReporter sends recovered panics to an error-reporting service like Sentry
+// ctx is the request context, so the reporter can tag the event with RequestIDFromContext.
+// Report is called synchronously before the 500 response is written, keep it fast.
+//
+// Usage:
+//
+//	type sentryReporter struct{}
+//
+//	func (sentryReporter) Report(ctx context.Context, err error, stack []byte) {
+//		hub := sentry.CurrentHub().Clone()
+//		hub.Scope().SetTag("request_id", httputil.GetRequestIDFromContext(ctx))
+//		hub.Scope().SetExtra("stack", string(stack))
+//		hub.CaptureException(err)
+//	}
+type ErrorReporter interface {
+	Report(ctx context.Context, err error, stack []byte)
+}
+
+// NopErrorReporter is an ErrorReporter dropping every report, the default of RecoveryMiddleware
+type NopErrorReporter struct{}
+
+// Report implements ErrorReporter
+func (NopErrorReporter) Report(context.Context, error, []byte) {}
+
+// RecoveryMiddleware recovers panics, logs them with the stack trace and request_id
+// and responds 500 with a JSON body containing the request_id so clients can quote it.
+// http.ErrAbortHandler is re-panicked to keep the net/http semantics of aborting the response.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.RecoveryMiddleware(logger))
+func RecoveryMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return RecoveryMiddlewareWithReporter(logger, nil)
+}
+
+// RecoveryMiddlewareWithReporter is RecoveryMiddleware also passing panics to reporter
+// Non-error panic values are wrapped into an error. A nil reporter is NopErrorReporter.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.RecoveryMiddlewareWithReporter(logger, sentryReporter{}))
+func RecoveryMiddlewareWithReporter(logger *slog.Logger, reporter ErrorReporter) gin.HandlerFunc {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if reporter == nil {
+		reporter = NopErrorReporter{}
+	}
+
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(rec)
+			}
+
+			requestID := GetRequestID(c)
+			stack := debug.Stack()
+			logger.LogAttrs(c.Request.Context(), slog.LevelError, "panic recovered",
+				slog.String("panic", fmt.Sprint(rec)),
+				slog.String("stack", string(stack)),
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.Request.URL.Path),
+				slog.String(LogKeyRequestID, requestID),
+			)
+			reporter.Report(c.Request.Context(), panicError(rec), stack)
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":      "Internal server error",
+				"request_id": requestID,
+			})
+		}()
+
+		c.Next()
+	}
+}
+
+// RecoveryHandler is the net/http counterpart of RecoveryMiddleware
+// Panics are logged with the stack trace and the request_id of the request context, then answered
+// with the same 500 JSON body. If the response was already partially written its status can't
+// change anymore, the panic is only logged. http.ErrAbortHandler is re-panicked.
+//
+// Usage:
+//
+//	handler := httputil.Chain(
+//		httputil.RequestIDHandler,
+//		httputil.RecoveryHandler(logger),
+//	).Then(mux)
+func RecoveryHandler(logger *slog.Logger) func(http.Handler) http.Handler {
+	return RecoveryHandlerWithReporter(logger, nil)
+}
+
+// RecoveryHandlerWithReporter is RecoveryHandler also passing panics to reporter, nil is NopErrorReporter
+func RecoveryHandlerWithReporter(logger *slog.Logger, reporter ErrorReporter) func(http.Handler) http.Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if reporter == nil {
+		reporter = NopErrorReporter{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := NewResponseRecorder(w)
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				if err, ok := p.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(p)
+				}
+
+				requestID := GetRequestIDFromContext(r.Context())
+				stack := debug.Stack()
+				logger.LogAttrs(r.Context(), slog.LevelError, "panic recovered",
+					slog.String("panic", fmt.Sprint(p)),
+					slog.String("stack", string(stack)),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.String(LogKeyRequestID, requestID),
+					slog.Bool("response_started", rec.StatusCode != 0),
+				)
+				reporter.Report(r.Context(), panicError(p), stack)
+
+				if rec.StatusCode != 0 {
+					return
+				}
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"error":      "Internal server error",
+					"request_id": requestID,
+				})
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// panicError returns a recovered panic value as an error
+func panicError(rec any) error {
+	if err, ok := rec.(error); ok {
+		return err
+	}
+	return fmt.Errorf("panic: %v", rec)
+}
@@ -0,0 +1,60 @@
+package httputil
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReplayGuardMiddleware rejects requests whose request ID was already seen within window with 409
+// A repeated X-Request-ID hints at a replayed request or a client reusing IDs; retries should send
+// a fresh request ID and keep the correlation ID. Seen IDs are kept in memory per middleware instance
+// and swept once per window, so memory stays bounded by about twice the request rate times window. The
+// check is per process, behind a balancer replicas don't share it. Mount it on sensitive routes
+// only, after RequestIDMiddleware.
+//
+// Usage:
+//
+//	payments.POST("/charge", httputil.ReplayGuardMiddleware(5*time.Minute), h.Charge)
+//
+// Response:
+//
+//	{"error": {"code": "duplicate_request_id", "message": "Request ID already used"}, "request_id": "..."}
+func ReplayGuardMiddleware(window time.Duration) gin.HandlerFunc {
+	var (
+		mu        sync.Mutex
+		seen      = make(map[string]time.Time)
+		lastSweep = time.Now()
+	)
+
+	// firstSeen records requestID and reports whether it was not seen within window
+	firstSeen := func(requestID string, now time.Time) bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if now.Sub(lastSweep) > window {
+			for id, at := range seen {
+				if now.Sub(at) > window {
+					delete(seen, id)
+				}
+			}
+			lastSweep = now
+		}
+
+		if at, ok := seen[requestID]; ok && now.Sub(at) <= window {
+			return false
+		}
+		seen[requestID] = now
+		return true
+	}
+
+	return func(c *gin.Context) {
+		if !firstSeen(GetRequestID(c), time.Now()) {
+			RespondError(c, http.StatusConflict, "duplicate_request_id", "Request ID already used")
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,138 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// RetryOptions configures DoWithRetry
+type RetryOptions struct {
+	// MaxAttempts is the total number of attempts including the first one, defaults to 3
+	MaxAttempts int
+
+	// BaseDelay is the delay before the second attempt, doubled for every next one, defaults to 100ms
+	BaseDelay time.Duration
+
+	// Retryable decides whether an attempt should be retried, defaults to DefaultRetryable
+	Retryable func(resp *http.Response, err error) bool
+}
+
+// DefaultRetryable retries transport errors and 5xx responses
+// Requests shed by an open circuit (ErrCircuitOpen) are not retried.
+func DefaultRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, ErrCircuitOpen)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// DoWithRetry sends req with exponential backoff between attempts
+// Every attempt carries the same X-Correlation-ID and its own X-Request-ID, so logs can
+// tell attempts apart while keeping them in one trace. The IDs are passed to the client's
+// PropagatingTransport in the request context, it writes them subject to its propagation policy;
+// a client whose Transport is not a *PropagatingTransport gets one on top. Waiting stops as soon as ctx is done.
+// Requests with a body are retried only if req.GetBody is set (http.NewRequest does it for
+// common body types). Bodies of discarded responses are drained and closed.
+//
+// Usage:
+//
+//	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+//	resp, err := httputil.DoWithRetry(ctx, client, req, httputil.RetryOptions{MaxAttempts: 5})
+func DoWithRetry(ctx context.Context, client *http.Client, req *http.Request, opts RetryOptions) (*http.Response, error) {
+	transport, ok := client.Transport.(*PropagatingTransport)
+	if !ok {
+		transport = NewPropagatingTransport(client.Transport)
+		propagating := *client
+		propagating.Transport = transport
+		client = &propagating
+	}
+	return doWithRetry(ctx, req, opts, transport.Config, client.Do)
+}
+
+// retryTransport is an http.RoundTripper applying DoWithRetry semantics to every request
+// It must wrap a PropagatingTransport, which writes the IDs of each attempt.
+type retryTransport struct {
+	base http.RoundTripper
+	opts RetryOptions
+
+	// cfg is the Config of the wrapped PropagatingTransport, naming the headers a caller may have set
+	cfg Config
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return doWithRetry(req.Context(), req, t.opts, t.cfg, t.base.RoundTrip)
+}
+
+// doWithRetry is the retry loop shared by DoWithRetry and retryTransport
+// cfg is the Config of the PropagatingTransport do sends through, it only names the headers read from req.
+func doWithRetry(ctx context.Context, req *http.Request, opts RetryOptions, cfg Config, do func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	cfg = cfg.withDefaults()
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = 100 * time.Millisecond
+	}
+	if opts.Retryable == nil {
+		opts.Retryable = DefaultRetryable
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		opts.MaxAttempts = 1
+	}
+
+	// the IDs travel in the attempt context, so the propagating transport applies its header names
+	// and policy instead of every attempt carrying the correlation ID to external hosts
+	propagated := withWrappedGinIDs(coreContext(ctx))
+	requestID := req.Header.Get(cfg.RequestIDHeader)
+	if requestID == "" {
+		requestID = GetRequestIDFromContext(propagated)
+	}
+	correlationID := req.Header.Get(cfg.CorrelationIDHeader)
+	if correlationID == "" {
+		correlationID = GetCorrelationIDFromContext(propagated)
+	}
+	if correlationID == "" {
+		correlationID = requestID
+	}
+	propagated = ContextWithCorrelationID(propagated, correlationID)
+
+	delay := opts.BaseDelay
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			requestID = NewRequestID()
+		}
+		attemptReq := req.Clone(ContextWithRequestID(propagated, requestID))
+		if attempt > 1 {
+			// a request ID set by the caller identifies the first attempt only
+			attemptReq.Header.Del(cfg.RequestIDHeader)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+		resp, err := do(attemptReq)
+		if ctx.Err() != nil || attempt == opts.MaxAttempts || !opts.Retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,32 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderRiskScore carries the fraud / abuse score computed at the edge, a number in [0, 1]
+// The request ID middlewares read it only from the proxies of Config.TrustedProxies, so clients can't
+// lower their own score: without TrustedProxies, or with TrustMode AlwaysRegenerate, the header is
+// ignored. Extract and the gRPC server interceptor read it only from trusted carriers, see ExtractOptions.
+// Outgoing requests forward it.
+const HeaderRiskScore = reqctx.HeaderRiskScore
+
+// ContextWithRiskScore creates a new context with the risk score, clamped to [0, 1]
+func ContextWithRiskScore(ctx context.Context, score float64) context.Context {
+	return reqctx.ContextWithRiskScore(ctx, score)
+}
+
+// RiskScoreFromContext returns the risk score and whether it was found
+// Out-of-range X-Risk-Score values are clamped to [0, 1] with a warning log. A *gin.Context is accepted too.
+//
+// Usage:
+//
+//	if score, ok := httputil.RiskScoreFromContext(c); ok && score > 0.8 {
+//		httputil.RespondError(c, http.StatusForbidden, "step_up_required", "additional verification required")
+//		return
+//	}
+func RiskScoreFromContext(ctx context.Context) (float64, bool) {
+	return reqctx.RiskScoreFromContext(valueContext(ctx))
+}
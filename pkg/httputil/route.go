@@ -0,0 +1,28 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// routeValue holds the matched route template of the request
+var routeValue = reqctx.NewContextValue[string]("route")
+
+// ContextWithRoute creates a new context with the matched route template, e.g. "/orders/:id"
+func ContextWithRoute(ctx context.Context, template string) context.Context {
+	return routeValue.With(ctx, template)
+}
+
+// RouteFromContext returns the matched gin route template and whether it was found
+// RequestIDMiddleware and ContextFromGin store c.FullPath(), so service code can log or authorize
+// by route pattern without gin. Unmatched requests (404) have no route. A *gin.Context is accepted too.
+//
+// Usage:
+//
+//	if route, ok := httputil.RouteFromContext(ctx); ok && !policy.Allows(user, route) {
+//		return ErrForbidden
+//	}
+func RouteFromContext(ctx context.Context) (string, bool) {
+	return routeValue.Get(valueContext(ctx))
+}
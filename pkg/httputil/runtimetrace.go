@@ -0,0 +1,30 @@
+package httputil
+
+import (
+	"context"
+	"runtime/trace"
+)
+
+// TraceRegion starts a runtime/trace region logged with the request_id of ctx, the returned func ends it
+// Execution traces (go tool trace) can then be sliced by request: the region's user log carries
+// request_id=<id>. Unlike ProfileLabelsMiddleware this targets execution traces, not CPU profiles.
+// When no trace is being collected nothing is started and a shared no-op func is returned.
+// End the region on the goroutine that started it. A *gin.Context is accepted as ctx too.
+//
+// Usage:
+//
+//	defer httputil.TraceRegion(ctx, "charge")()
+func TraceRegion(ctx context.Context, name string) func() {
+	if !trace.IsEnabled() {
+		return endNoopRegion
+	}
+	ctx = valueContext(ctx)
+	region := trace.StartRegion(ctx, name)
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		trace.Log(ctx, LogKeyRequestID, requestID)
+	}
+	return region.End
+}
+
+// endNoopRegion is returned by TraceRegion while tracing is disabled
+func endNoopRegion() {}
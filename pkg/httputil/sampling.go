@@ -0,0 +1,38 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderTraceSampled carries the upstream sampling decision, "1" for sampled and "0" for not sampled
+// The request ID middlewares read it from trusted requests only (see Config.TrustedProxies), so
+// clients can't force verbose logging, and ignore it with TrustMode AlwaysRegenerate.
+// Outgoing requests forward it.
+const HeaderTraceSampled = reqctx.HeaderTraceSampled
+
+// ContextWithSampled creates a new context with the sampling decision
+func ContextWithSampled(ctx context.Context, sampled bool) context.Context {
+	return reqctx.ContextWithSampled(ctx, sampled)
+}
+
+// IsSampled reports whether the request is sampled for verbose logs and traces
+// Requests without an X-Trace-Sampled decision are sampled. A *gin.Context is accepted too.
+//
+// Usage:
+//
+//	if httputil.IsSampled(c) {
+//		logger.Debug("request payload", "payload", payload, "request_id", httputil.GetRequestID(c))
+//	}
+func IsSampled(ctx context.Context) bool {
+	return reqctx.IsSampled(valueContext(ctx))
+}
+
+// contextWithSampledHeader stores a valid X-Trace-Sampled value in ctx, invalid values are ignored
+func contextWithSampledHeader(ctx context.Context, value string) context.Context {
+	if sampled, ok := reqctx.ParseSampled(value); ok {
+		return ContextWithSampled(ctx, sampled)
+	}
+	return ctx
+}
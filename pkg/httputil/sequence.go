@@ -0,0 +1,35 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderTraceSequence carries the hop number within a trace, incremented on every propagation
+// The request ID middlewares read it into the request context, outgoing requests send it plus one.
+const HeaderTraceSequence = reqctx.HeaderTraceSequence
+
+// ContextWithSequence creates a new context with the hop number of the current service
+func ContextWithSequence(ctx context.Context, seq int) context.Context {
+	return reqctx.ContextWithSequence(ctx, seq)
+}
+
+// SequenceFromContext returns the hop number within the trace, 0 at the origin
+// A *gin.Context is accepted too. See reqctx.SequenceFromContext.
+//
+// Usage:
+//
+//	logger.Info("payment captured", "request_id", httputil.GetRequestID(c),
+//		"trace_sequence", httputil.SequenceFromContext(c))
+func SequenceFromContext(ctx context.Context) int {
+	return reqctx.SequenceFromContext(valueContext(ctx))
+}
+
+// contextWithSequenceHeader stores a valid X-Trace-Sequence value in ctx, invalid values are ignored
+func contextWithSequenceHeader(ctx context.Context, value string) context.Context {
+	if seq, ok := reqctx.ParseSequence(value); ok {
+		return ContextWithSequence(ctx, seq)
+	}
+	return ctx
+}
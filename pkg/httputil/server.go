@@ -0,0 +1,29 @@
+package httputil
+
+import "net/http"
+
+// ConfigureServer wraps srv.Handler with RequestIDHandlerWithConfig for pure net/http servers
+// http.DefaultServeMux is wrapped if srv.Handler is nil. srv.BaseContext and srv.ConnContext
+// are kept: the per-request context still derives from them, values put there (server-wide logger,
+// shutdown signal) stay visible to handlers, and the request and correlation IDs are layered on top,
+// so GetRequestIDFromContext(r.Context()) returns the request's own ID even if the base context
+// already carries one. Call it once, before ListenAndServe.
+//
+// Usage:
+//
+//	srv := &http.Server{
+//		Addr:    ":8080",
+//		Handler: mux,
+//		BaseContext: func(net.Listener) context.Context {
+//			return appCtx
+//		},
+//	}
+//	httputil.ConfigureServer(srv, httputil.DefaultConfig())
+//	log.Fatal(srv.ListenAndServe())
+func ConfigureServer(srv *http.Server, cfg Config) {
+	handler := srv.Handler
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+	srv.Handler = RequestIDHandlerWithConfig(cfg)(handler)
+}
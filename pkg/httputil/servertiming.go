@@ -0,0 +1,87 @@
+package httputil
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HeaderServerTiming is the response header read by the browser PerformanceServerTiming API
+	HeaderServerTiming = "Server-Timing"
+
+	// ServerTimingMetricName is the Server-Timing metric carrying the request ID
+	ServerTimingMetricName = "traceId"
+)
+
+// ServerTimingMiddleware adds the request ID to the Server-Timing response header as traceId;desc="<id>"
+// Lets frontend RUM events read the ID from PerformanceResourceTiming.serverTiming and correlate with
+// backend traces. The entry is appended when the response header is written, so Server-Timing
+// values set by handlers, including with Header().Set, are kept.
+// Mount it after RequestIDMiddleware. Cross-origin pages also need Timing-Allow-Origin to see the header.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.ServerTimingMiddleware())
+func ServerTimingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w := &serverTimingWriter{ResponseWriter: c.Writer, c: c}
+		c.Writer = w
+		c.Next()
+
+		// gin writes the header of empty responses after the chain through its own writer
+		w.addEntry()
+	}
+}
+
+// serverTimingEntry formats the Server-Timing entry for requestID
+// desc is a quoted-string, request IDs are visible ASCII so strconv.Quote only escapes '"' and '\'
+func serverTimingEntry(requestID string) string {
+	return ServerTimingMetricName + ";desc=" + strconv.Quote(requestID)
+}
+
+// serverTimingWriter appends the request ID Server-Timing entry right before the header is written
+type serverTimingWriter struct {
+	gin.ResponseWriter
+	c     *gin.Context
+	added bool
+}
+
+// addEntry appends the entry once, as long as the header has not been sent yet
+func (w *serverTimingWriter) addEntry() {
+	if w.added || w.ResponseWriter.Written() {
+		return
+	}
+	w.added = true
+	w.Header().Add(HeaderServerTiming, serverTimingEntry(GetRequestID(w.c)))
+}
+
+// WriteHeaderNow implements gin.ResponseWriter
+func (w *serverTimingWriter) WriteHeaderNow() {
+	w.addEntry()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write implements http.ResponseWriter
+func (w *serverTimingWriter) Write(b []byte) (int, error) {
+	w.addEntry()
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString implements gin.ResponseWriter
+func (w *serverTimingWriter) WriteString(s string) (int, error) {
+	w.addEntry()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush implements http.Flusher
+func (w *serverTimingWriter) Flush() {
+	w.addEntry()
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (w *serverTimingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,115 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// SessionIDKey is the gin.Context key of the session ID stored by SessionMiddleware
+	SessionIDKey = "session_id"
+
+	// LogKeySessionID is the log attribute key for session ID
+	LogKeySessionID = "session_id"
+
+	// DefaultSessionCookieName is the cookie SessionMiddleware uses if SessionConfig.CookieName is empty
+	DefaultSessionCookieName = "sid"
+)
+
+// sessionIDKey is the context key for session ID
+const sessionIDKey contextKey = "session_id"
+
+// SessionConfig configures SessionMiddlewareWithConfig
+type SessionConfig struct {
+	// CookieName is the cookie carrying the session ID, DefaultSessionCookieName if empty
+	CookieName string
+
+	// SameSite is the SameSite attribute of a newly set cookie, http.SameSiteLaxMode if zero
+	SameSite http.SameSite
+
+	// Secure sends the cookie over HTTPS only, set it for every service not served over plain HTTP
+	Secure bool
+
+	// Path is the cookie path, "/" if empty
+	Path string
+
+	// Domain is the cookie domain, empty limits the cookie to the host of the request
+	Domain string
+
+	// MaxAge is the cookie lifetime, zero makes it a browser session cookie
+	MaxAge time.Duration
+}
+
+// SessionMiddleware groups requests of one browser session by a session ID cookie
+// See SessionMiddlewareWithConfig.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.SessionMiddleware())
+func SessionMiddleware() gin.HandlerFunc {
+	return SessionMiddlewareWithConfig(SessionConfig{})
+}
+
+// SessionMiddlewareWithConfig stores the session ID cookie in gin.Context and the request context
+// A missing cookie or one failing ValidateRequestID is replaced by a new ID and set on the response
+// (HttpOnly). The session_id is added to log records by LoggerFromContext, ContextHandler and
+// AccessLogMiddleware, complementing the per-request request_id. It only correlates logs and must
+// not be used for authentication.
+//
+// Usage:
+//
+//	router.Use(httputil.SessionMiddlewareWithConfig(httputil.SessionConfig{
+//		CookieName: "trace_sid",
+//		SameSite:   http.SameSiteStrictMode,
+//		Secure:     true,
+//	}))
+func SessionMiddlewareWithConfig(cfg SessionConfig) gin.HandlerFunc {
+	if cfg.CookieName == "" {
+		cfg.CookieName = DefaultSessionCookieName
+	}
+	if cfg.SameSite == 0 {
+		cfg.SameSite = http.SameSiteLaxMode
+	}
+	if cfg.Path == "" {
+		cfg.Path = "/"
+	}
+
+	return func(c *gin.Context) {
+		sessionID, err := c.Cookie(cfg.CookieName)
+		if err != nil || ValidateRequestID(sessionID) != nil {
+			sessionID = NewRequestID()
+			http.SetCookie(c.Writer, &http.Cookie{
+				Name:     cfg.CookieName,
+				Value:    sessionID,
+				Path:     cfg.Path,
+				Domain:   cfg.Domain,
+				MaxAge:   int(cfg.MaxAge / time.Second),
+				Secure:   cfg.Secure,
+				HttpOnly: true,
+				SameSite: cfg.SameSite,
+			})
+		}
+
+		c.Set(SessionIDKey, sessionID)
+		c.Request = c.Request.WithContext(ContextWithSessionID(c.Request.Context(), sessionID))
+		c.Next()
+	}
+}
+
+// ContextWithSessionID creates a new context with session_id value
+func ContextWithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey, sessionID)
+}
+
+// SessionIDFromContext returns the session_id and whether it was found
+// A *gin.Context is accepted too, gin storage is looked up first.
+func SessionIDFromContext(ctx context.Context) (string, bool) {
+	if sessionID := ginStoreValue(ctx, SessionIDKey); sessionID != "" {
+		return sessionID, true
+	}
+	sessionID, _ := valueContext(ctx).Value(sessionIDKey).(string)
+	return sessionID, sessionID != ""
+}
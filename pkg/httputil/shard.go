@@ -0,0 +1,17 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// RequestIDShard returns a stable shard index in [0, numShards) derived from the request ID of ctx
+// A *gin.Context is accepted too. See reqctx.RequestIDShard.
+//
+// Usage:
+//
+//	buffers[httputil.RequestIDShard(c, len(buffers))].Append(line)
+func RequestIDShard(ctx context.Context, numShards int) int {
+	return reqctx.RequestIDShard(coreContext(ctx), numShards)
+}
@@ -0,0 +1,120 @@
+package httputil
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderRequestIDSignature carries the hex HMAC-SHA256 of X-Request-ID
+const HeaderRequestIDSignature = "X-Request-ID-Sig"
+
+// SignatureVerifyMiddleware rejects requests whose X-Request-ID doesn't match X-Request-ID-Sig with 401
+// The signature is the hex HMAC-SHA256 of the request ID with secret, as set by SignRequestID, and is
+// compared in constant time. Requests without X-Request-ID pass, the request ID middleware generates
+// one for them. Requests with several request ID values, repeated headers or a comma-separated list,
+// are rejected with 400: only one of them could be signed. Mount it in front of RequestIDMiddleware,
+// so a spoofed ID is never stored; the rejection carries a fresh request ID instead of the rejected one.
+//
+// Usage:
+//
+//	router.Use(httputil.SignatureVerifyMiddleware(secret), httputil.RequestIDMiddleware())
+//
+// Response:
+//
+//	{"error": {"code": "invalid_request_id_signature", "message": "Request ID signature mismatch"}, "request_id": "..."}
+func SignatureVerifyMiddleware(secret []byte) gin.HandlerFunc {
+	return SignatureVerifyMiddlewareWithConfig(DefaultConfig(), secret)
+}
+
+// SignatureVerifyMiddlewareWithConfig is SignatureVerifyMiddleware for the request ID middleware configured with cfg
+// It verifies the value RequestIDMiddlewareWithConfig(cfg) adopts: the cfg.RequestIDHeader value or, with
+// Config.UseAmznTraceID or Config.UseCloudTraceContext, the trace ID it falls back to.
+//
+// Usage:
+//
+//	cfg := httputil.Config{RequestIDHeader: "Request-Id"}
+//	router.Use(httputil.SignatureVerifyMiddlewareWithConfig(cfg, secret), httputil.RequestIDMiddlewareWithConfig(cfg))
+func SignatureVerifyMiddlewareWithConfig(cfg Config, secret []byte) gin.HandlerFunc {
+	cfg = cfg.withDefaults()
+
+	return func(c *gin.Context) {
+		requestID, ok := signedRequestID(c.Request, cfg)
+		if !ok {
+			_ = SetRequestID(c, NewRequestID())
+			RespondError(c, http.StatusBadRequest, "ambiguous_request_id", "Request carries several request IDs")
+			return
+		}
+		if requestID == "" {
+			c.Next()
+			return
+		}
+
+		signature, err := hex.DecodeString(strings.TrimSpace(headerGet(c.Request.Header, HeaderRequestIDSignature)))
+		if err != nil || !hmac.Equal(signature, requestIDSignature(requestID, secret)) {
+			_ = SetRequestID(c, NewRequestID())
+			RespondError(c, http.StatusUnauthorized, "invalid_request_id_signature", "Request ID signature mismatch")
+			return
+		}
+		c.Next()
+	}
+}
+
+// signedRequestID returns the incoming request ID the request ID middleware would adopt under cfg
+// ok is false if r carries several non-empty request ID values. The fallbacks to X-Amzn-Trace-Id and
+// X-Cloud-Trace-Context apply as in resolveIDs, without trust checks: verifying too much is harmless.
+func signedRequestID(r *http.Request, cfg Config) (requestID string, ok bool) {
+	var values []string
+	for _, value := range headerValues(r.Header, cfg.RequestIDHeader) {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+	switch {
+	case len(values) > 1:
+		return "", false
+	case len(values) == 1:
+		return values[0], true
+	case trustedValue(firstHeaderValue(r.Header, cfg.CorrelationIDHeader)) != "":
+		return "", true
+	case cfg.UseAmznTraceID:
+		if root := strings.TrimSpace(amznTraceRoot(headerGet(r.Header, HeaderAmznTraceID))); root != "" {
+			return root, true
+		}
+	}
+	if cfg.UseCloudTraceContext {
+		if trace, ok := ParseCloudTraceContext(headerGet(r.Header, HeaderCloudTraceContext)); ok {
+			return trace.TraceID, true
+		}
+	}
+	return "", true
+}
+
+// SignRequestID sets X-Request-ID-Sig for the X-Request-ID of req, call it after the tracing headers are set
+// Nothing is set if req has no request ID.
+//
+// Usage:
+//
+//	httputil.PropagateRequestIDFromContext(ctx, req)
+//	httputil.SignRequestID(req, secret)
+//	resp, err := client.Do(req)
+func SignRequestID(req *http.Request, secret []byte) {
+	requestID := req.Header.Get(HeaderRequestID)
+	if requestID == "" {
+		return
+	}
+	req.Header.Set(HeaderRequestIDSignature, hex.EncodeToString(requestIDSignature(requestID, secret)))
+}
+
+// requestIDSignature returns the HMAC-SHA256 of requestID with secret
+func requestIDSignature(requestID string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(requestID))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,135 @@
+package httputil
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSignatureVerifyMiddleware(t *testing.T) {
+	secret := []byte("secret")
+	sign := func(id string) string {
+		return hex.EncodeToString(requestIDSignature(id, secret))
+	}
+
+	tests := []struct {
+		name       string
+		cfg        Config
+		header     http.Header
+		wantStatus int
+		wantID     string
+	}{
+		{
+			name:       "no request ID",
+			header:     http.Header{},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "valid signature",
+			header:     http.Header{"X-Request-Id": {"abc"}, "X-Request-Id-Sig": {sign("abc")}},
+			wantStatus: http.StatusOK,
+			wantID:     "abc",
+		},
+		{
+			name:       "missing signature",
+			header:     http.Header{"X-Request-Id": {"spoofed"}},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong signature",
+			header:     http.Header{"X-Request-Id": {"spoofed"}, "X-Request-Id-Sig": {sign("abc")}},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "empty value before unsigned value",
+			header:     http.Header{"X-Request-Id": {"", "spoofed"}},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "empty value before signed value",
+			header:     http.Header{"X-Request-Id": {"", "abc"}, "X-Request-Id-Sig": {sign("abc")}},
+			wantStatus: http.StatusOK,
+			wantID:     "abc",
+		},
+		{
+			name:       "signed value followed by spoofed value",
+			header:     http.Header{"X-Request-Id": {"abc", "spoofed"}, "X-Request-Id-Sig": {sign("abc")}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "repeated identical values",
+			header:     http.Header{"X-Request-Id": {"abc", "abc"}, "X-Request-Id-Sig": {sign("abc")}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "comma-separated values",
+			header:     http.Header{"X-Request-Id": {"abc, spoofed"}, "X-Request-Id-Sig": {sign("abc")}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "non-canonical header name",
+			header:     http.Header{"x-request-id": {"spoofed"}},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "custom header unsigned",
+			cfg:        Config{RequestIDHeader: "Request-Id"},
+			header:     http.Header{"Request-Id": {"spoofed"}},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "custom header signed",
+			cfg:        Config{RequestIDHeader: "Request-Id"},
+			header:     http.Header{"Request-Id": {"abc"}, "X-Request-Id-Sig": {sign("abc")}},
+			wantStatus: http.StatusOK,
+			wantID:     "abc",
+		},
+		{
+			name:       "unsigned amzn trace fallback",
+			cfg:        Config{UseAmznTraceID: true},
+			header:     http.Header{"X-Amzn-Trace-Id": {"Root=1-67891233-abcdef012345678912345678"}},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(SignatureVerifyMiddlewareWithConfig(tt.cfg, secret), RequestIDMiddlewareWithConfig(tt.cfg))
+			var gotID string
+			router.GET("/", func(c *gin.Context) {
+				gotID = GetRequestID(c)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header = tt.header
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantID != "" && gotID != tt.wantID {
+				t.Errorf("request ID = %q, want %q", gotID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestSignRequestID(t *testing.T) {
+	secret := []byte("secret")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	SignRequestID(req, secret)
+	if got := req.Header.Get(HeaderRequestIDSignature); got != "" {
+		t.Fatalf("signature without request ID = %q, want none", got)
+	}
+
+	req.Header.Set(HeaderRequestID, "abc")
+	SignRequestID(req, secret)
+	if got, want := req.Header.Get(HeaderRequestIDSignature), hex.EncodeToString(requestIDSignature("abc", secret)); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+package httputil
+
+import "strings"
+
+// pathMatcher matches request paths against exact paths and prefixes
+// A path ending with "*" is a prefix: "/debug/*" matches "/debug/pprof/heap"
+type pathMatcher struct {
+	exact    map[string]struct{}
+	prefixes []string
+}
+
+// newPathMatcher builds a pathMatcher from exact paths and "*"-suffixed prefixes
+func newPathMatcher(paths []string) pathMatcher {
+	m := pathMatcher{exact: make(map[string]struct{}, len(paths))}
+	for _, path := range paths {
+		if prefix, ok := strings.CutSuffix(path, "*"); ok {
+			m.prefixes = append(m.prefixes, prefix)
+			continue
+		}
+		m.exact[path] = struct{}{}
+	}
+	return m
+}
+
+// match reports whether path is one of the exact paths or starts with one of the prefixes
+func (m pathMatcher) match(path string) bool {
+	if _, ok := m.exact[path]; ok {
+		return true
+	}
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// empty reports whether the matcher matches nothing
+func (m pathMatcher) empty() bool {
+	return len(m.exact) == 0 && len(m.prefixes) == 0
+}
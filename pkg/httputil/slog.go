@@ -0,0 +1,198 @@
+package httputil
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// LoggerKey is the gin context key of the request-scoped logger stored by LoggerMiddleware
+const LoggerKey = "logger"
+
+const (
+	// LogKeyRequestID is the log attribute key for request ID
+	LogKeyRequestID = reqctx.LogKeyRequestID
+
+	// LogKeyCorrelationID is the log attribute key for correlation ID
+	LogKeyCorrelationID = reqctx.LogKeyCorrelationID
+)
+
+// LoggerFromContext returns a child of base with request_id and correlation_id attributes from ctx
+// slog.Default() is used if base is nil. IDs missing in ctx are not added and never generated,
+// session_id is added when SessionMiddleware stored one, app_version with Config.Version, upload_part
+// for PartContext and Cloud Logging trace fields with SetCloudTraceProjectID.
+//
+// Usage:
+//
+//	log := httputil.LoggerFromContext(ctx, h.log)
+//	log.Info("order created", "order_id", order.ID)
+func LoggerFromContext(ctx context.Context, base *slog.Logger) *slog.Logger {
+	if base == nil {
+		base = slog.Default()
+	}
+
+	attrs := traceAttrs(ctx)
+	if len(attrs) == 0 {
+		return base
+	}
+
+	args := make([]any, len(attrs))
+	for i, attr := range attrs {
+		args[i] = attr
+	}
+	return base.With(args...)
+}
+
+// LoggerMiddleware stores a child of base with request_id and correlation_id in gin.Context
+// The logger is built once per request, handlers get it with Logger(c). slog.Default() is used
+// if base is nil. Mount it after RequestIDMiddleware so the IDs are known.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.LoggerMiddleware(logger))
+func LoggerMiddleware(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(LoggerKey, LoggerFromContext(c, base))
+		c.Next()
+	}
+}
+
+// Logger returns the request-scoped logger stored by LoggerMiddleware
+// Without the middleware it falls back to LoggerFromContext(c, nil), a child of slog.Default().
+//
+// Usage:
+//
+//	func (h *Handler) CreateOrder(c *gin.Context) {
+//		log := httputil.Logger(c)
+//		log.Info("order created", "order_id", order.ID)
+//	}
+func Logger(c *gin.Context) *slog.Logger {
+	if value, ok := c.Get(LoggerKey); ok {
+		if logger, ok := value.(*slog.Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return LoggerFromContext(c, nil)
+}
+
+// ExtractRequestIDFromText returns the first request ID logged in line, in logfmt or JSON form
+// See reqctx.ExtractRequestIDFromText.
+func ExtractRequestIDFromText(line string) (string, bool) {
+	return reqctx.ExtractRequestIDFromText(line)
+}
+
+// ContextHandler is a slog.Handler that adds request_id and correlation_id from the record context
+// The IDs are always top-level attributes: groups opened with WithGroup wrap the record's own
+// attributes and those added after the group, never the injected IDs, so log queries on
+// request_id work for every logger derived from the handler.
+type ContextHandler struct {
+	// next has the attributes added before the first group
+	next slog.Handler
+
+	// groups are the open groups with the attributes added inside them, outermost first
+	groups []logGroup
+}
+
+// logGroup is a group opened with WithGroup and the attributes added while it was innermost
+type logGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// NewContextHandler wraps next so that records logged with a context carry its trace IDs
+//
+// Usage:
+//
+//	logger := slog.New(httputil.NewContextHandler(slog.NewJSONHandler(os.Stdout, nil)))
+//	logger.InfoContext(ctx, "order created")
+func NewContextHandler(next slog.Handler) *ContextHandler {
+	return &ContextHandler{next: next}
+}
+
+// Enabled implements slog.Handler
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := traceAttrs(ctx)
+	if len(h.groups) == 0 {
+		if len(attrs) > 0 {
+			record = record.Clone()
+			record.AddAttrs(attrs...)
+		}
+		return h.next.Handle(ctx, record)
+	}
+
+	// rebuild the record with its attributes nested in the open groups and the IDs beside them
+	nested := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		nested = append(nested, attr)
+		return true
+	})
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		group := h.groups[i]
+		nested = append(append([]slog.Attr(nil), group.attrs...), nested...)
+		nested = []slog.Attr{{Key: group.name, Value: slog.GroupValue(nested...)}}
+	}
+
+	grouped := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	grouped.AddAttrs(nested...)
+	grouped.AddAttrs(attrs...)
+	return h.next.Handle(ctx, grouped)
+}
+
+// WithAttrs implements slog.Handler
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	if len(h.groups) == 0 {
+		return &ContextHandler{next: h.next.WithAttrs(attrs)}
+	}
+
+	groups := append([]logGroup(nil), h.groups...)
+	last := &groups[len(groups)-1]
+	last.attrs = append(append([]slog.Attr(nil), last.attrs...), attrs...)
+	return &ContextHandler{next: h.next, groups: groups}
+}
+
+// WithGroup implements slog.Handler
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := append(append([]logGroup(nil), h.groups...), logGroup{name: name})
+	return &ContextHandler{next: h.next, groups: groups}
+}
+
+// traceAttrs returns log attributes for the IDs stored in ctx
+func traceAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+
+	requestID, correlationID := idsFromContext(ctx)
+
+	var attrs []slog.Attr
+	if requestID != "" {
+		attrs = append(attrs, slog.String(LogKeyRequestID, requestID))
+	}
+	if correlationID != "" {
+		attrs = append(attrs, slog.String(LogKeyCorrelationID, correlationID))
+	}
+	if sessionID, ok := SessionIDFromContext(ctx); ok {
+		attrs = append(attrs, slog.String(LogKeySessionID, sessionID))
+	}
+	if version, ok := AppVersionFromContext(ctx); ok {
+		attrs = append(attrs, slog.String(LogKeyAppVersion, version))
+	}
+	if part, ok := UploadPartFromContext(ctx); ok {
+		attrs = append(attrs, slog.String(LogKeyUploadPart, part))
+	}
+	return append(attrs, cloudTraceAttrs(ctx)...)
+}
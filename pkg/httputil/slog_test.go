@@ -0,0 +1,128 @@
+package httputil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+// testJSONOptions drop the time, level and msg keys so records compare by their attributes only
+var testJSONOptions = &slog.HandlerOptions{
+	ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+		if len(groups) == 0 && (attr.Key == slog.TimeKey || attr.Key == slog.LevelKey || attr.Key == slog.MessageKey) {
+			return slog.Attr{}
+		}
+		return attr
+	},
+}
+
+// logJSON logs msg through the handler built by build around a JSON handler and returns the decoded record
+func logJSON(t *testing.T, ctx context.Context, build func(slog.Handler) slog.Handler, args ...any) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	slog.New(build(NewContextHandler(slog.NewJSONHandler(&buf, testJSONOptions)))).InfoContext(ctx, "msg", args...)
+	return decodeJSON(t, buf.Bytes())
+}
+
+func decodeJSON(t *testing.T, data []byte) map[string]any {
+	t.Helper()
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decode %q: %v", data, err)
+	}
+	return got
+}
+
+func TestContextHandlerChaining(t *testing.T) {
+	ctx := ContextWithCorrelationID(ContextWithRequestID(context.Background(), "req-1"), "corr-1")
+
+	tests := []struct {
+		name  string
+		build func(slog.Handler) slog.Handler
+		args  []any
+		want  map[string]any
+	}{
+		{
+			name:  "no groups",
+			build: func(h slog.Handler) slog.Handler { return h.WithAttrs([]slog.Attr{slog.String("a", "1")}) },
+			args:  []any{"b", "2"},
+			want:  map[string]any{"a": "1", "b": "2", "request_id": "req-1", "correlation_id": "corr-1"},
+		},
+		{
+			name:  "WithGroup",
+			build: func(h slog.Handler) slog.Handler { return h.WithGroup("g") },
+			args:  []any{"b", "2"},
+			want:  map[string]any{"g": map[string]any{"b": "2"}, "request_id": "req-1", "correlation_id": "corr-1"},
+		},
+		{
+			name: "WithGroup then WithAttrs",
+			build: func(h slog.Handler) slog.Handler {
+				return h.WithGroup("g").WithAttrs([]slog.Attr{slog.String("a", "1")})
+			},
+			args: []any{"b", "2"},
+			want: map[string]any{"g": map[string]any{"a": "1", "b": "2"}, "request_id": "req-1", "correlation_id": "corr-1"},
+		},
+		{
+			name: "WithAttrs then WithGroup",
+			build: func(h slog.Handler) slog.Handler {
+				return h.WithAttrs([]slog.Attr{slog.String("a", "1")}).WithGroup("g")
+			},
+			args: []any{"b", "2"},
+			want: map[string]any{"a": "1", "g": map[string]any{"b": "2"}, "request_id": "req-1", "correlation_id": "corr-1"},
+		},
+		{
+			name: "nested groups with attrs",
+			build: func(h slog.Handler) slog.Handler {
+				return h.WithAttrs([]slog.Attr{slog.String("a", "1")}).
+					WithGroup("outer").WithAttrs([]slog.Attr{slog.String("b", "2")}).
+					WithGroup("inner").WithAttrs([]slog.Attr{slog.String("c", "3")})
+			},
+			args: []any{"d", "4"},
+			want: map[string]any{
+				"a":              "1",
+				"outer":          map[string]any{"b": "2", "inner": map[string]any{"c": "3", "d": "4"}},
+				"request_id":     "req-1",
+				"correlation_id": "corr-1",
+			},
+		},
+		{
+			name:  "empty group name and attrs",
+			build: func(h slog.Handler) slog.Handler { return h.WithGroup("").WithAttrs(nil) },
+			args:  []any{"b", "2"},
+			want:  map[string]any{"b": "2", "request_id": "req-1", "correlation_id": "corr-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logJSON(t, ctx, tt.build, tt.args...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("record = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextHandlerSiblings(t *testing.T) {
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	var buf bytes.Buffer
+	group := NewContextHandler(slog.NewJSONHandler(&buf, testJSONOptions)).WithGroup("g").WithAttrs([]slog.Attr{slog.String("a", "1")})
+
+	// handlers derived from the same parent must not share the parent's attribute slice
+	first := group.WithAttrs([]slog.Attr{slog.String("first", "1")})
+	second := group.WithAttrs([]slog.Attr{slog.String("second", "2")})
+
+	slog.New(first).InfoContext(ctx, "msg")
+	gotFirst := decodeJSON(t, buf.Bytes())
+	buf.Reset()
+	slog.New(second).InfoContext(ctx, "msg")
+	gotSecond := decodeJSON(t, buf.Bytes())
+
+	if want := map[string]any{"g": map[string]any{"a": "1", "first": "1"}, "request_id": "req-1"}; !reflect.DeepEqual(gotFirst, want) {
+		t.Errorf("first = %v, want %v", gotFirst, want)
+	}
+	if want := map[string]any{"g": map[string]any{"a": "1", "second": "2"}, "request_id": "req-1"}; !reflect.DeepEqual(gotSecond, want) {
+		t.Errorf("second = %v, want %v", gotSecond, want)
+	}
+}
@@ -0,0 +1,33 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// Snapshot freezes the tracing identity of a context for retry loops, see reqctx.Snapshot
+type Snapshot = reqctx.Snapshot
+
+// SnapshotContext captures ctx so every attempt gets a new request ID and the same everything else
+// A *gin.Context is accepted too. See reqctx.SnapshotContext.
+//
+// Usage:
+//
+//	snap := httputil.SnapshotContext(c)
+//	for snap.Attempts() < 3 {
+//		attemptCtx := snap.NewAttempt()
+//		if err = h.payments.Charge(attemptCtx, order); err == nil {
+//			break
+//		}
+//		httputil.LoggerFromContext(attemptCtx, h.log).Warn("charge failed",
+//			"attempt", httputil.AttemptFromContext(attemptCtx), "error", err)
+//	}
+func SnapshotContext(ctx context.Context) Snapshot {
+	return reqctx.SnapshotContext(coreContext(ctx))
+}
+
+// AttemptFromContext returns the attempt number of a Snapshot.NewAttempt context, 0 for other contexts
+func AttemptFromContext(ctx context.Context) int {
+	return reqctx.AttemptFromContext(valueContext(ctx))
+}
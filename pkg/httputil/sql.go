@@ -0,0 +1,106 @@
+package httputil
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// QueryTagsFromContext returns request-scoped identifiers for tagging database query logs
+// Tags are request_id, correlation_id, tenant_id and user_id, each only if present in ctx.
+// Nothing is generated: background queries without a request get ok == false.
+//
+// Usage:
+//
+//	func (l *queryLogger) Log(ctx context.Context, sql string, elapsed time.Duration) {
+//		attrs := []any{"sql", sql, "elapsed", elapsed}
+//		if tags, ok := httputil.QueryTagsFromContext(ctx); ok {
+//			for k, v := range tags {
+//				attrs = append(attrs, k, v)
+//			}
+//		}
+//		l.log.Warn("slow query", attrs...)
+//	}
+func QueryTagsFromContext(ctx context.Context) (map[string]string, bool) {
+	tags := make(map[string]string, 4)
+
+	requestID, correlationID := idsFromContext(ctx)
+	if requestID != "" {
+		tags[LogKeyRequestID] = requestID
+	}
+	if correlationID != "" {
+		tags[LogKeyCorrelationID] = correlationID
+	}
+	if tenantID := GetTenantIDFromContext(valueContext(ctx)); tenantID != "" {
+		tags[TenantIDKey] = tenantID
+	}
+	if userID := GetUserIDFromContext(valueContext(ctx)); userID != "" {
+		tags[UserIDKey] = userID
+	}
+
+	return tags, len(tags) > 0
+}
+
+// QueryCommentFormat selects the SQL comment syntax of QueryContextTagsWithFormat
+type QueryCommentFormat int
+
+const (
+	// SQLCommenterFormat follows the sqlcommenter spec: /*correlation_id='c1',request_id='r1'*/
+	// Keys are sorted, values URL-encoded and quoted. Understood by Cloud SQL Insights and similar tools.
+	SQLCommenterFormat QueryCommentFormat = iota
+
+	// KeyValueFormat is the plain /* request_id=r1 correlation_id=c1 */ form for grepping Postgres logs
+	// Values are URL-encoded like in SQLCommenterFormat.
+	KeyValueFormat
+)
+
+// QueryContextTags formats request_id and correlation_id of ctx as a SQL comment in SQLCommenterFormat
+// Prepend it to queries so pg_stat_activity and slow query logs can be matched with request traces.
+// Returns an empty string if ctx has neither ID, nothing is generated.
+// Values are URL-encoded, so no ID can close the comment or inject SQL.
+//
+// Usage:
+//
+//	rows, err := db.QueryContext(ctx, httputil.QueryContextTags(ctx)+"SELECT * FROM orders WHERE id = $1", id)
+func QueryContextTags(ctx context.Context) string {
+	return QueryContextTagsWithFormat(ctx, SQLCommenterFormat)
+}
+
+// QueryContextTagsWithFormat is QueryContextTags with a selectable comment syntax
+// A trailing space is included when the result is not empty, so it can be prepended as is.
+func QueryContextTagsWithFormat(ctx context.Context, format QueryCommentFormat) string {
+	requestID, correlationID := idsFromContext(ctx)
+	if requestID == "" && correlationID == "" {
+		return ""
+	}
+
+	// ordered by key, as sqlcommenter requires
+	tags := make([][2]string, 0, 2)
+	if correlationID != "" {
+		tags = append(tags, [2]string{LogKeyCorrelationID, correlationID})
+	}
+	if requestID != "" {
+		tags = append(tags, [2]string{LogKeyRequestID, requestID})
+	}
+
+	var b strings.Builder
+	switch format {
+	case KeyValueFormat:
+		b.WriteString("/*")
+		for i := len(tags) - 1; i >= 0; i-- {
+			b.WriteString(" " + tags[i][0] + "=" + url.PathEscape(tags[i][1]))
+		}
+		b.WriteString(" */ ")
+	default:
+		b.WriteString("/*")
+		for i, tag := range tags {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			// PathEscape also encodes quotes, '*' and '/', nothing is left to escape inside the quotes
+			b.WriteString(tag[0] + "='" + url.PathEscape(tag[1]) + "'")
+		}
+		b.WriteString("*/ ")
+	}
+	return b.String()
+}
@@ -0,0 +1,186 @@
+package httputil
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Option configures the middleware stack built by Middlewares
+type Option func(*stackOptions)
+
+type stackOptions struct {
+	config    Config
+	logger    *slog.Logger
+	skipPaths []string
+	metrics   gin.HandlerFunc
+	clock     Clock
+
+	servedBy      string
+	version       string
+	profileLabels bool
+	contextHooks  []func(ctx context.Context) context.Context
+	errorReporter ErrorReporter
+}
+
+// WithConfig sets the request ID middleware configuration
+func WithConfig(cfg Config) Option {
+	return func(o *stackOptions) {
+		o.config = cfg
+	}
+}
+
+// WithLogger sets the logger for recovery and access log middlewares, slog.Default() by default
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *stackOptions) {
+		o.logger = logger
+	}
+}
+
+// WithServicePrefix prefixes request IDs generated by the stack, see SetServicePrefix
+func WithServicePrefix(name string) Option {
+	return func(o *stackOptions) {
+		o.config.ServicePrefix = name
+	}
+}
+
+// WithShortIDs makes the stack generate ShortIDGenerator IDs instead of UUIDs
+// See ShortIDGenerator for the collision probability tradeoff
+func WithShortIDs() Option {
+	return func(o *stackOptions) {
+		o.config.IDGenerator = ShortIDGenerator{}
+	}
+}
+
+// WithSkipPaths excludes paths such as "/healthz" from request ID handling, access logging and metrics
+// A path ending with "*" is a prefix: "/debug/*" skips everything under /debug/.
+// Skipped requests still reach their handler and stay covered by recovery.
+func WithSkipPaths(paths ...string) Option {
+	return func(o *stackOptions) {
+		o.skipPaths = append(o.skipPaths, paths...)
+	}
+}
+
+// WithMetrics appends a metrics middleware, e.g. promutil.MetricsMiddleware()
+// Metrics live in a separate package so httputil doesn't depend on Prometheus
+func WithMetrics(metrics gin.HandlerFunc) Option {
+	return func(o *stackOptions) {
+		o.metrics = metrics
+	}
+}
+
+// WithServedByHeader makes responses carry X-Served-By: name next to the request ID
+// An empty name means os.Hostname(), the pod name in Kubernetes. Opt-in because it exposes
+// internal hostnames, see Config.ServedBy.
+func WithServedByHeader(name string) Option {
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+	return func(o *stackOptions) {
+		o.servedBy = name
+	}
+}
+
+// WithVersionHeader makes responses carry X-App-Version: version and logs it as app_version
+// An empty version means the Version variable set via -ldflags. Opt-in, for external endpoints
+// too, because it tells clients which build they hit, see Config.Version.
+func WithVersionHeader(version string) Option {
+	if version == "" {
+		version = Version
+	}
+	return func(o *stackOptions) {
+		o.version = version
+	}
+}
+
+// WithProfileLabels labels pprof profiles with the request ID, see ProfileLabelsMiddleware
+// Opt-in because of the small per-request cost.
+func WithProfileLabels() Option {
+	return func(o *stackOptions) {
+		o.profileLabels = true
+	}
+}
+
+// WithContextHook adds a Config.ContextHooks entry to the request ID middleware of the stack
+// Hooks set with WithConfig are kept, this one runs after them.
+func WithContextHook(hook func(ctx context.Context) context.Context) Option {
+	return func(o *stackOptions) {
+		o.contextHooks = append(o.contextHooks, hook)
+	}
+}
+
+// WithErrorReporter passes panics caught by the recovery middleware to reporter
+func WithErrorReporter(reporter ErrorReporter) Option {
+	return func(o *stackOptions) {
+		o.errorReporter = reporter
+	}
+}
+
+// WithClock sets the clock the access log measures latency with, SystemClock by default
+func WithClock(clock Clock) Option {
+	return func(o *stackOptions) {
+		o.clock = clock
+	}
+}
+
+// Middlewares returns the recommended tracing middleware stack in the correct order:
+//  1. request ID - everything after it, including panic and access logs, sees the request_id
+//     (followed by pprof labels with WithProfileLabels)
+//  2. recovery - outside logging and metrics, so a panic anywhere below is caught and
+//     logged with its stack and request_id instead of killing the connection
+//  3. access log - measures the handler and logs the final status
+//  4. metrics - innermost, records the status the handler actually produced
+//
+// Usage:
+//
+//	router := gin.New()
+//	router.Use(httputil.Middlewares(
+//		httputil.WithLogger(logger),
+//		httputil.WithSkipPaths("/healthz", "/debug/*"),
+//		httputil.WithMetrics(promutil.MetricsMiddleware()),
+//	)...)
+func Middlewares(opts ...Option) []gin.HandlerFunc {
+	var o stackOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	cfg := o.config
+	cfg.SkipPaths = append(append([]string(nil), cfg.SkipPaths...), o.skipPaths...)
+	if o.servedBy != "" {
+		cfg.ServedBy = o.servedBy
+	}
+	if o.version != "" {
+		cfg.Version = o.version
+	}
+	cfg.ContextHooks = append(append([]func(context.Context) context.Context(nil), cfg.ContextHooks...), o.contextHooks...)
+
+	handlers := []gin.HandlerFunc{RequestIDMiddlewareWithConfig(cfg)}
+	if o.profileLabels {
+		handlers = append(handlers, skipPaths(newPathMatcher(o.skipPaths), ProfileLabelsMiddleware()))
+	}
+	handlers = append(handlers,
+		RecoveryMiddlewareWithReporter(o.logger, o.errorReporter),
+		AccessLogMiddlewareWithConfig(AccessLogConfig{Logger: o.logger, SkipPaths: o.skipPaths, Clock: o.clock}),
+	)
+	if o.metrics != nil {
+		handlers = append(handlers, skipPaths(newPathMatcher(o.skipPaths), o.metrics))
+	}
+	return handlers
+}
+
+// skipPaths wraps handler so that it is bypassed for requests matching skip
+func skipPaths(skip pathMatcher, handler gin.HandlerFunc) gin.HandlerFunc {
+	if skip.empty() {
+		return handler
+	}
+	return func(c *gin.Context) {
+		if skip.match(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+		handler(c)
+	}
+}
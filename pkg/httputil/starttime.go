@@ -0,0 +1,57 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HeaderRequestStart carries the start time of the original edge request in unix milliseconds
+const HeaderRequestStart = "X-Request-Start"
+
+// startTimeKey is the context key for request start time
+const startTimeKey contextKey = "request_start"
+
+// ContextWithStartTime creates a new context with the request start time
+func ContextWithStartTime(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, startTimeKey, start)
+}
+
+// StartTimeFromContext returns the start time of the original edge request and whether it was found
+// It is recorded by the request ID middlewares with Config.RecordStartTime.
+//
+// Usage:
+//
+//	if start, ok := httputil.StartTimeFromContext(ctx); ok {
+//		log.Info("order created", "since_edge", time.Since(start))
+//	}
+func StartTimeFromContext(ctx context.Context) (time.Time, bool) {
+	start, ok := valueContext(ctx).Value(startTimeKey).(time.Time)
+	return start, ok
+}
+
+// PropagateStartTime writes X-Request-Start with the start time stored in ctx
+// Nothing is written if ctx has no start time
+//
+// Usage:
+//
+//	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+//	httputil.PropagateStartTime(ctx, req)
+func PropagateStartTime(ctx context.Context, req *http.Request) {
+	start, ok := StartTimeFromContext(ctx)
+	if !ok {
+		return
+	}
+	req.Header.Set(HeaderRequestStart, strconv.FormatInt(start.UnixMilli(), 10))
+}
+
+// incomingStartTime returns the start time from X-Request-Start, or now if it is missing or malformed
+// so the current hop becomes the origin
+func incomingStartTime(h http.Header) time.Time {
+	ms, err := strconv.ParseInt(headerGet(h, HeaderRequestStart), 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Now()
+	}
+	return time.UnixMilli(ms)
+}
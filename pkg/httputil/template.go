@@ -0,0 +1,28 @@
+package httputil
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateKeyRequestID is the key under which HTML renders the request ID into template data
+const TemplateKeyRequestID = "request_id"
+
+// HTML renders an HTML template like c.HTML with the request ID added to data
+// Works with templates loaded by LoadHTMLGlob/LoadHTMLFiles, data is not modified.
+//
+// Usage:
+//
+//	httputil.HTML(c, http.StatusNotFound, "error.tmpl", gin.H{"message": "Page not found"})
+//
+// error.tmpl:
+//
+//	<p>{{ .message }}</p>
+//	<small>Reference: {{ .request_id }}</small>
+func HTML(c *gin.Context, status int, name string, data gin.H) {
+	withID := make(gin.H, len(data)+1)
+	for k, v := range data {
+		withID[k] = v
+	}
+	withID[TemplateKeyRequestID] = GetRequestID(c)
+	c.HTML(status, name, withID)
+}
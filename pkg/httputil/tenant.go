@@ -0,0 +1,113 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// HeaderTenantID carries the tenant the request acts for
+const HeaderTenantID = reqctx.HeaderTenantID
+
+// MaxTenantIDLength is the maximum length of a tenant ID accepted by the default validator
+const MaxTenantIDLength = reqctx.MaxTenantIDLength
+
+// ErrInvalidTenantID is returned by ValidateTenantID for malformed tenant IDs
+var ErrInvalidTenantID = reqctx.ErrInvalidTenantID
+
+// TenantConfig configures TenantMiddlewareWithConfig
+type TenantConfig struct {
+	// FromRequest resolves the tenant ID before the X-Tenant-ID header is looked at,
+	// e.g. from a verified JWT claim. ok == false falls back to the header.
+	FromRequest func(c *gin.Context) (tenantID string, ok bool)
+
+	// IgnoreHeader disables the X-Tenant-ID header, only FromRequest is used
+	// For public endpoints where clients must not choose the tenant.
+	IgnoreHeader bool
+
+	// Required rejects requests without a tenant ID with 400
+	Required bool
+}
+
+// TenantMiddleware stores the X-Tenant-ID header in gin.Context and the request context
+// Tenant IDs failing ValidateTenantID are rejected with 400, requests without the header pass through.
+// The tenant ID is forwarded downstream by PropagatingTransport and the other propagation helpers.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.TenantMiddleware())
+//	router.GET("/orders", func(c *gin.Context) {
+//		tenantID, _ := httputil.TenantIDFromContext(c)
+//		...
+//	})
+func TenantMiddleware() gin.HandlerFunc {
+	return TenantMiddlewareWithConfig(TenantConfig{})
+}
+
+// TenantMiddlewareWithConfig is TenantMiddleware with a claim hook and a mandatory tenant option
+//
+// Usage:
+//
+//	router.Use(authMiddleware, httputil.TenantMiddlewareWithConfig(httputil.TenantConfig{
+//		FromRequest: func(c *gin.Context) (string, bool) {
+//			claims, ok := c.Get("claims")
+//			if !ok {
+//				return "", false
+//			}
+//			return claims.(*Claims).TenantID, true
+//		},
+//		IgnoreHeader: true,
+//		Required:     true,
+//	}))
+func TenantMiddlewareWithConfig(cfg TenantConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var tenantID string
+		if cfg.FromRequest != nil {
+			tenantID, _ = cfg.FromRequest(c)
+		}
+		if tenantID == "" && !cfg.IgnoreHeader {
+			tenantID = c.GetHeader(HeaderTenantID)
+		}
+
+		if tenantID == "" {
+			if cfg.Required {
+				RespondError(c, http.StatusBadRequest, "missing_tenant_id", "Missing tenant ID")
+				return
+			}
+			c.Next()
+			return
+		}
+		if err := ValidateTenantID(tenantID); err != nil {
+			RespondError(c, http.StatusBadRequest, "invalid_tenant_id", err.Error())
+			return
+		}
+
+		c.Set(TenantIDKey, tenantID)
+		c.Request = c.Request.WithContext(ContextWithTenantID(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
+
+// ValidateTenantID checks a tenant ID with the validator set by SetTenantIDValidator
+// The default accepts 1 to MaxTenantIDLength ASCII letters, digits, '-' and '_'.
+func ValidateTenantID(tenantID string) error {
+	return reqctx.ValidateTenantID(tenantID)
+}
+
+// SetTenantIDValidator replaces the tenant ID format check, nil restores the default
+// Safe for concurrent use.
+func SetTenantIDValidator(validate func(tenantID string) error) {
+	reqctx.SetTenantIDValidator(validate)
+}
+
+// TenantIDFromContext returns the tenant_id and whether it was found
+// A *gin.Context is accepted too, gin storage is looked up first.
+func TenantIDFromContext(ctx context.Context) (string, bool) {
+	if tenantID := ginStoreValue(ctx, TenantIDKey); tenantID != "" {
+		return tenantID, true
+	}
+	return reqctx.TenantIDFromContext(valueContext(ctx))
+}
@@ -0,0 +1,36 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutMiddleware sets a per-request deadline on c.Request.Context()
+// The derived context carries request_id and correlation_id, so GetRequestIDFromContext
+// keeps returning the right value downstream. The timeout is cooperative: handlers must
+// respect ctx. If the deadline was exceeded and nothing was written, it responds 503
+// with the request_id.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), httputil.TimeoutMiddleware(5*time.Second))
+func TimeoutMiddleware(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(ContextFromGin(c), d)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":      "Request timeout",
+				"request_id": GetRequestID(c),
+			})
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+)
+
+// tracedError remembers the request ID of the context it was created with
+type tracedError struct {
+	err       error
+	requestID string
+}
+
+// Error returns the message of the wrapped error, the request ID is not included
+func (e *tracedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error for errors.Is and errors.As
+func (e *tracedError) Unwrap() error {
+	return e.err
+}
+
+// NewError creates an error carrying the request ID of ctx, see RequestIDFromError
+// A plain error is returned if ctx has no request ID.
+//
+// Usage:
+//
+//	return httputil.NewError(ctx, "inventory reservation failed")
+func NewError(ctx context.Context, msg string) error {
+	return Wrap(ctx, errors.New(msg))
+}
+
+// Wrap attaches the request ID of ctx to err, keeping errors.Is and errors.As working
+// The message is unchanged. err is returned as is if it is nil or ctx has no request ID.
+//
+// Usage:
+//
+//	if err := h.repo.Save(ctx, order); err != nil {
+//		return httputil.Wrap(ctx, err)
+//	}
+func Wrap(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	requestID, _ := idsFromContext(ctx)
+	if requestID == "" {
+		return err
+	}
+	return &tracedError{err: err, requestID: requestID}
+}
+
+// RequestIDFromError returns the request ID recorded by NewError or Wrap anywhere in err's chain
+// Returns empty string if there is none. With several, the outermost wins.
+//
+// Usage:
+//
+//	if err := worker.Run(); err != nil {
+//		log.Error("job failed", "error", err, "request_id", httputil.RequestIDFromError(err))
+//	}
+func RequestIDFromError(err error) string {
+	var traced *tracedError
+	if errors.As(err, &traced) {
+		return traced.requestID
+	}
+	return ""
+}
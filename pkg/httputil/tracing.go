@@ -0,0 +1,27 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// TracingValues are the core tracing identifiers of a request, see reqctx.TracingValues
+type TracingValues = reqctx.TracingValues
+
+// ContextWithTracing stores the non-empty fields of values in ctx with a single context node
+// See reqctx.ContextWithTracing.
+//
+// Usage:
+//
+//	ctx = httputil.ContextWithTracing(ctx, httputil.TracingValues{RequestID: job.RequestID, TenantID: job.TenantID})
+func ContextWithTracing(ctx context.Context, values TracingValues) context.Context {
+	return reqctx.ContextWithTracing(ctx, values)
+}
+
+// TracingFromContext returns the tracing identifiers stored in ctx with one lookup, missing ones are empty
+// Values kept only in gin.Context by SetRequestID are not included, use GetRequestID for those.
+// A *gin.Context is accepted too.
+func TracingFromContext(ctx context.Context) TracingValues {
+	return reqctx.TracingFromContext(valueContext(ctx))
+}
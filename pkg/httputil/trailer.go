@@ -0,0 +1,52 @@
+package httputil
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDTrailer sends the request ID as an X-Request-ID trailer after a streamed response body
+// For streaming endpoints (SSE, gRPC-web, chunked downloads) where headers may be flushed before
+// the request ID is known or by code that drops them. Call it before streaming and defer the
+// returned func, which sets the trailer once the body is done. Trailers need HTTP/1.1 chunked
+// encoding or HTTP/2: for HTTP/1.0 clients and responses with a fixed Content-Length the returned
+// func does nothing and a debug message is logged.
+//
+// Usage:
+//
+//	router.GET("/events", func(c *gin.Context) {
+//		defer httputil.RequestIDTrailer(c)()
+//		c.Stream(func(w io.Writer) bool {
+//			...
+//		})
+//	})
+func RequestIDTrailer(c *gin.Context) func() {
+	if !c.Writer.Written() {
+		// declared trailers are advertised to clients, some only read announced ones
+		c.Writer.Header().Add("Trailer", HeaderRequestID)
+	}
+
+	return func() {
+		requestID := SanitizeHeaderValue(GetRequestID(c))
+		if !trailersSupported(c.Request, c.Writer.Header()) {
+			slog.DebugContext(c.Request.Context(), "httputil: trailers not supported, request ID trailer skipped",
+				slog.String("proto", c.Request.Proto),
+				slog.String(LogKeyRequestID, requestID),
+			)
+			return
+		}
+		if headerListContains(c.Writer.Header().Values("Trailer"), HeaderRequestID) {
+			c.Writer.Header().Set(HeaderRequestID, requestID)
+			return
+		}
+		// undeclared trailers set after the header was written need the prefix
+		c.Writer.Header().Set(http.TrailerPrefix+HeaderRequestID, requestID)
+	}
+}
+
+// trailersSupported reports whether trailers can be delivered for the response
+func trailersSupported(r *http.Request, h http.Header) bool {
+	return r.ProtoAtLeast(1, 1) && h.Get("Content-Length") == ""
+}
@@ -0,0 +1,94 @@
+package httputil
+
+import (
+	"net/http"
+)
+
+// PropagatingTransport is an http.RoundTripper that adds request ID and Baggage headers to every outgoing request
+// Values are taken from req.Context(); headers already set on the request are left untouched.
+// Only hosts listed in InternalHosts get more than the request ID.
+// The request ID sent is recorded for ChildRequestIDsFromContext if the context has a collector.
+type PropagatingTransport struct {
+	// Base is the underlying RoundTripper, http.DefaultTransport is used if nil
+	Base http.RoundTripper
+
+	// Config selects the header names, defaults are used for empty fields
+	Config Config
+
+	// InternalHosts lists the hosts receiving all tracing headers, see PolicyForHost
+	// Other hosts receive only the request ID, so tenant, client IP, baggage or an Idempotency-Key
+	// never reach third-party APIs. Empty means every host is external, "*" makes every host internal.
+	InternalHosts []string
+
+	// Formats lists the propagation formats to send, only the native headers if empty
+	// Include NativeFormat to send the native headers alongside B3 or Datadog ones.
+	Formats []Format
+}
+
+// NewPropagatingTransport wraps base with request ID propagation
+// Any RoundTripper works as base, http.DefaultTransport is used if nil. The transport never mutates
+// the incoming request and skips headers already set, so it composes with SDK transport chains:
+// layered under a signing transport it adds headers after signing (they stay out of the signature),
+// layered over it the headers are added first and signed along with the request. Stacking two
+// PropagatingTransports is harmless, the inner one finds the headers set.
+//
+// Usage:
+//
+//	transport := httputil.NewPropagatingTransport(http.DefaultTransport)
+//	transport.InternalHosts = []string{"*.internal.example.com"}
+//	client := &http.Client{Transport: transport}
+//	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+//	resp, err := client.Do(req)
+//
+//	// SDK transport chains: pass the propagating transport as the SDK's base
+//	sdkClient := &http.Client{Transport: sdk.NewSigningTransport(httputil.NewPropagatingTransport(nil))}
+func NewPropagatingTransport(base http.RoundTripper) *PropagatingTransport {
+	return &PropagatingTransport{Base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *PropagatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cfg := t.Config.withDefaults()
+
+	ctx := req.Context()
+
+	headers := outgoingHeaders(ctx, cfg)
+	if requestID := req.Header.Get(cfg.RequestIDHeader); requestID != "" && GetCorrelationIDFromContext(ctx) == "" {
+		// an explicit request ID also serves as the default correlation ID
+		headers[cfg.CorrelationIDHeader] = requestID
+	}
+	headers = policyHeaders(headers, cfg, PolicyForHost(req.URL.Host, t.InternalHosts...))
+	childID := headers[cfg.RequestIDHeader]
+	if requestID := req.Header.Get(cfg.RequestIDHeader); requestID != "" {
+		childID = requestID
+	}
+	if len(t.Formats) > 0 {
+		// other formats encode the request ID actually sent
+		headers[cfg.RequestIDHeader] = childID
+		headers = formatHeaders(headers, cfg, t.Formats)
+	}
+	for key := range headers {
+		if req.Header.Get(key) != "" {
+			delete(headers, key)
+		}
+	}
+	RecordChildRequestID(ctx, childID)
+	if len(headers) == 0 {
+		return t.base().RoundTrip(req)
+	}
+
+	// RoundTripper must not modify the request, so headers are set on a clone
+	req = req.Clone(ctx)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	return t.base().RoundTrip(req)
+}
+
+func (t *PropagatingTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
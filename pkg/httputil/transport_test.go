@@ -0,0 +1,302 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// recordHeaders returns a RoundTripper answering 204 and storing the headers of the last request in got
+func recordHeaders(got *http.Header) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		*got = req.Header.Clone()
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Request: req}, nil
+	})
+}
+
+func TestPropagatingTransportPolicy(t *testing.T) {
+	ctx := reqctx.ContextWithTracing(context.Background(), reqctx.TracingValues{
+		RequestID:     "req-1",
+		CorrelationID: "corr-1",
+		TenantID:      "acme",
+	})
+	ctx = ContextWithIdempotencyKey(ctx, "idem-1")
+
+	tests := []struct {
+		name          string
+		internalHosts []string
+		url           string
+		wantFull      bool
+	}{
+		{name: "no internal hosts", url: "https://api.stripe.com/v1/charges", wantFull: false},
+		{name: "no internal hosts, internal-looking name", url: "http://billing/orders", wantFull: false},
+		{name: "matching wildcard", internalHosts: []string{"*.internal.example.com"}, url: "http://orders.internal.example.com:8080/", wantFull: true},
+		{name: "not matching", internalHosts: []string{"*.internal.example.com"}, url: "https://api.stripe.com/", wantFull: false},
+		{name: "star", internalHosts: []string{"*"}, url: "https://api.stripe.com/", wantFull: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got http.Header
+			transport := &PropagatingTransport{Base: recordHeaders(&got), InternalHosts: tt.internalHosts}
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, tt.url, nil)
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if id := got.Get(HeaderRequestID); id != "req-1" {
+				t.Errorf("%s = %q, want req-1", HeaderRequestID, id)
+			}
+			for _, name := range []string{HeaderCorrelationID, HeaderTenantID, HeaderIdempotencyKey} {
+				if sent := got.Get(name) != ""; sent != tt.wantFull {
+					t.Errorf("%s sent = %v, want %v", name, sent, tt.wantFull)
+				}
+			}
+		})
+	}
+}
+
+func TestNewTracingClientInternalHosts(t *testing.T) {
+	ctx := ContextWithCorrelationID(ContextWithRequestID(context.Background(), "req-1"), "corr-1")
+
+	for _, tt := range []struct {
+		name     string
+		opts     []ClientOption
+		wantFull bool
+	}{
+		{name: "default", wantFull: false},
+		{name: "WithInternalHosts", opts: []ClientOption{WithInternalHosts("orders.internal")}, wantFull: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewTracingClient(tt.opts...)
+			propagating := client.Transport.(*PropagatingTransport)
+			var got http.Header
+			propagating.Base = recordHeaders(&got)
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://orders.internal/", nil)
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if sent := got.Get(HeaderCorrelationID) != ""; sent != tt.wantFull {
+				t.Errorf("%s sent = %v, want %v", HeaderCorrelationID, sent, tt.wantFull)
+			}
+		})
+	}
+}
+
+// signingTransport mimics an SDK signer: it lists the headers present when it runs in X-Signed-Headers
+// and passes a clone of the request to next
+func signingTransport(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		names := make([]string, 0, len(req.Header))
+		for name := range req.Header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		req = req.Clone(req.Context())
+		req.Header.Set("X-Signed-Headers", strings.Join(names, ";"))
+		return next.RoundTrip(req)
+	})
+}
+
+func TestPropagatingTransportSDKChain(t *testing.T) {
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+
+	tests := []struct {
+		name       string
+		chain      func(base http.RoundTripper) http.RoundTripper
+		header     http.Header
+		wantID     string
+		wantSigned bool
+	}{
+		{
+			name: "under the signer",
+			chain: func(base http.RoundTripper) http.RoundTripper {
+				return signingTransport(NewPropagatingTransport(base))
+			},
+			wantID:     "req-1",
+			wantSigned: false,
+		},
+		{
+			name: "over the signer",
+			chain: func(base http.RoundTripper) http.RoundTripper {
+				return NewPropagatingTransport(signingTransport(base))
+			},
+			wantID:     "req-1",
+			wantSigned: true,
+		},
+		{
+			name: "stacked twice",
+			chain: func(base http.RoundTripper) http.RoundTripper {
+				return NewPropagatingTransport(signingTransport(NewPropagatingTransport(base)))
+			},
+			wantID:     "req-1",
+			wantSigned: true,
+		},
+		{
+			name: "header set by the caller",
+			chain: func(base http.RoundTripper) http.RoundTripper {
+				return signingTransport(NewPropagatingTransport(base))
+			},
+			header:     http.Header{HeaderRequestID: {"explicit"}},
+			wantID:     "explicit",
+			wantSigned: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got http.Header
+			client := &http.Client{Transport: tt.chain(recordHeaders(&got))}
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://s3.amazonaws.com/bucket", nil)
+			for name, values := range tt.header {
+				req.Header.Set(name, values[0])
+			}
+			original := req.Header.Clone()
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if ids := got.Values(HeaderRequestID); len(ids) != 1 || ids[0] != tt.wantID {
+				t.Errorf("%s = %q, want [%s]", HeaderRequestID, ids, tt.wantID)
+			}
+			signed := strings.Contains(";"+got.Get("X-Signed-Headers")+";", ";"+http.CanonicalHeaderKey(HeaderRequestID)+";")
+			if signed != tt.wantSigned {
+				t.Errorf("%s signed = %v, want %v (signed headers %q)", HeaderRequestID, signed, tt.wantSigned, got.Get("X-Signed-Headers"))
+			}
+			if len(req.Header) != len(original) || req.Header.Get(HeaderRequestID) != original.Get(HeaderRequestID) {
+				t.Errorf("request headers mutated: %v, want %v", req.Header, original)
+			}
+		})
+	}
+}
+
+// failingOnce returns a RoundTripper answering 500 to the first request and 204 afterwards,
+// storing the headers of every request in got
+func failingOnce(got *[]http.Header) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		*got = append(*got, req.Header.Clone())
+		status := http.StatusNoContent
+		if len(*got) == 1 {
+			status = http.StatusInternalServerError
+		}
+		return &http.Response{StatusCode: status, Body: http.NoBody, Request: req}, nil
+	})
+}
+
+func TestNewTracingClientRetryPolicy(t *testing.T) {
+	ctx := ContextWithCorrelationID(ContextWithRequestID(context.Background(), "req-1"), "corr-1")
+	retry := WithRetry(RetryOptions{MaxAttempts: 2, BaseDelay: time.Millisecond})
+
+	for _, tt := range []struct {
+		name            string
+		opts            []ClientOption
+		wantCorrelation string
+	}{
+		{name: "no internal hosts", opts: []ClientOption{retry}},
+		{name: "internal host", opts: []ClientOption{retry, WithInternalHosts("api.example.com")}, wantCorrelation: "corr-1"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewTracingClient(tt.opts...)
+			var got []http.Header
+			client.Transport.(*retryTransport).base.(*PropagatingTransport).Base = failingOnce(&got)
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.example.com/v1", nil)
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if len(got) != 2 {
+				t.Fatalf("attempts = %d, want 2", len(got))
+			}
+			if got[0].Get(HeaderRequestID) != "req-1" || got[1].Get(HeaderRequestID) == "" || got[1].Get(HeaderRequestID) == "req-1" {
+				t.Errorf("%s = %q, %q, want req-1 then a new ID", HeaderRequestID, got[0].Get(HeaderRequestID), got[1].Get(HeaderRequestID))
+			}
+			for i, header := range got {
+				if correlation := header.Get(HeaderCorrelationID); correlation != tt.wantCorrelation {
+					t.Errorf("attempt %d %s = %q, want %q", i+1, HeaderCorrelationID, correlation, tt.wantCorrelation)
+				}
+			}
+		})
+	}
+}
+
+func TestDoWithRetryPlainClient(t *testing.T) {
+	ctx := ContextWithCorrelationID(ContextWithRequestID(context.Background(), "req-1"), "corr-1")
+	var got []http.Header
+	client := &http.Client{Transport: failingOnce(&got)}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.example.com/v1", nil)
+	resp, err := DoWithRetry(ctx, client, req, RetryOptions{MaxAttempts: 2, BaseDelay: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(got) != 2 {
+		t.Fatalf("attempts = %d, want 2", len(got))
+	}
+	for i, header := range got {
+		if header.Get(HeaderRequestID) == "" {
+			t.Errorf("attempt %d has no %s", i+1, HeaderRequestID)
+		}
+		if correlation := header.Get(HeaderCorrelationID); correlation != "" {
+			t.Errorf("attempt %d %s = %q sent to an external host", i+1, HeaderCorrelationID, correlation)
+		}
+	}
+}
+
+func TestDoWithRetryCustomHeaders(t *testing.T) {
+	ctx := ContextWithCorrelationID(context.Background(), "corr-1")
+	var got []http.Header
+	client := &http.Client{Transport: &PropagatingTransport{
+		Base:          failingOnce(&got),
+		Config:        Config{RequestIDHeader: "Request-Id", CorrelationIDHeader: "Correlation-Id"},
+		InternalHosts: []string{"*"},
+	}}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://orders.internal/", nil)
+	req.Header.Set("Request-Id", "explicit")
+	resp, err := DoWithRetry(ctx, client, req, RetryOptions{MaxAttempts: 2, BaseDelay: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(got) != 2 {
+		t.Fatalf("attempts = %d, want 2", len(got))
+	}
+	if first, second := got[0].Get("Request-Id"), got[1].Get("Request-Id"); first != "explicit" || second == "" || second == first {
+		t.Errorf("Request-Id = %q, %q, want explicit then a new ID", first, second)
+	}
+	for i, header := range got {
+		if correlation := header.Get("Correlation-Id"); correlation != "corr-1" {
+			t.Errorf("attempt %d Correlation-Id = %q, want corr-1", i+1, correlation)
+		}
+		for _, name := range []string{HeaderRequestID, HeaderCorrelationID} {
+			if value := header.Get(name); value != "" {
+				t.Errorf("attempt %d sent default header %s = %q", i+1, name, value)
+			}
+		}
+	}
+}
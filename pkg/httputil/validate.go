@@ -0,0 +1,53 @@
+package httputil
+
+import "github.com/TRAD3R/common/pkg/reqctx"
+
+// DefaultMaxRequestIDLength is the default maximum length of an accepted request ID
+const DefaultMaxRequestIDLength = reqctx.DefaultMaxRequestIDLength
+
+var (
+	// ErrEmptyRequestID is returned when an empty request ID is supplied
+	ErrEmptyRequestID = reqctx.ErrEmptyRequestID
+
+	// ErrRequestIDTooLong is returned when a request ID exceeds the maximum length
+	ErrRequestIDTooLong = reqctx.ErrRequestIDTooLong
+
+	// ErrInvalidRequestID is returned when a request ID contains CR/LF, spaces or non-printable characters
+	ErrInvalidRequestID = reqctx.ErrInvalidRequestID
+)
+
+// SetMaxRequestIDLength changes the maximum accepted request ID length
+// Values <= 0 restore DefaultMaxRequestIDLength. Safe for concurrent use.
+func SetMaxRequestIDLength(n int) {
+	reqctx.SetMaxRequestIDLength(n)
+}
+
+// ValidateRequestID checks that id is safe to trust and echo in headers
+// Only visible ASCII characters are allowed. The returned error is one of
+// ErrEmptyRequestID, ErrRequestIDTooLong or ErrInvalidRequestID.
+//
+// Usage:
+//
+//	if err := httputil.ValidateRequestID(id); err != nil {
+//		log.Warn("request id replaced", "reason", err)
+//	}
+func ValidateRequestID(id string) error {
+	return reqctx.ValidateRequestID(id)
+}
+
+// SanitizeHeaderValue removes CR, LF and other control characters from v
+// Use it before echoing any externally influenced value in a response header.
+func SanitizeHeaderValue(v string) string {
+	return reqctx.SanitizeHeaderValue(v)
+}
+
+// headerSafeID sanitizes id and generates a fresh ID if nothing usable is left
+func headerSafeID(id string, cfg Config) string {
+	if id = SanitizeHeaderValue(id); id != "" {
+		return id
+	}
+	if id = SanitizeHeaderValue(cfg.newRequestID()); id != "" {
+		return id
+	}
+	return UUIDGenerator{}.Generate()
+}
@@ -0,0 +1,33 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// SetContextValue stores value for v in the request context of c
+// Handlers and services reading ContextFromGin(c) or c.Request.Context() see it. A nil c.Request is left alone.
+//
+// Usage:
+//
+//	var Locale = reqctx.NewContextValue[string]("locale")
+//
+//	func LocaleMiddleware(c *gin.Context) {
+//		httputil.SetContextValue(c, Locale, c.GetHeader("Accept-Language"))
+//		c.Next()
+//	}
+func SetContextValue[T any](c *gin.Context, v reqctx.ContextValue[T], value T) {
+	if c.Request == nil {
+		return
+	}
+	c.Request = c.Request.WithContext(v.With(c.Request.Context(), value))
+}
+
+// GetContextValue returns the value for v and whether it was found
+// Unlike v.Get it also accepts a *gin.Context, reading its request context.
+func GetContextValue[T any](ctx context.Context, v reqctx.ContextValue[T]) (T, bool) {
+	return v.Get(valueContext(ctx))
+}
@@ -0,0 +1,30 @@
+package httputil
+
+import (
+	"context"
+
+	"github.com/TRAD3R/common/pkg/reqctx"
+)
+
+// Version is the build version used by WithVersionHeader when none is given
+// Set it at build time:
+//
+//	go build -ldflags "-X github.com/TRAD3R/common/pkg/httputil.Version=$(git describe --tags)"
+var Version string
+
+const (
+	// HeaderAppVersion carries the version of the code that served the request, see Config.Version
+	HeaderAppVersion = "X-App-Version"
+
+	// LogKeyAppVersion is the log attribute key for the serving version
+	LogKeyAppVersion = "app_version"
+)
+
+// appVersionValue holds the version of the service handling the request
+var appVersionValue = reqctx.NewContextValue[string]("app_version")
+
+// AppVersionFromContext returns the version stored by the request ID middleware with Config.Version
+// A *gin.Context is accepted too.
+func AppVersionFromContext(ctx context.Context) (string, bool) {
+	return appVersionValue.Get(valueContext(ctx))
+}
@@ -0,0 +1,62 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebSocketUpgradeHeader returns the response header to pass to a WebSocket upgrader
+// Upgraders that hijack the connection (gorilla/websocket) write only the header they are given,
+// not c.Writer.Header(), so the X-Request-ID and X-Correlation-ID set by RequestIDMiddleware
+// would be lost. The IDs are also set on c.Writer.Header() for upgraders that use it.
+// A request ID is generated and stored in c if none is set yet.
+//
+// Usage:
+//
+//	conn, err := upgrader.Upgrade(c.Writer, c.Request, httputil.WebSocketUpgradeHeader(c))
+func WebSocketUpgradeHeader(c *gin.Context) http.Header {
+	requestID, _ := EnsureRequestID(c)
+	correlationID := c.GetString(CorrelationIDKey)
+	if correlationID == "" {
+		correlationID = requestID
+	}
+
+	h := make(http.Header, 2)
+	h.Set(HeaderRequestID, SanitizeHeaderValue(requestID))
+	h.Set(HeaderCorrelationID, SanitizeHeaderValue(correlationID))
+	if !c.Writer.Written() {
+		c.Writer.Header().Set(HeaderRequestID, h.Get(HeaderRequestID))
+		c.Writer.Header().Set(HeaderCorrelationID, h.Get(HeaderCorrelationID))
+	}
+	return h
+}
+
+// WebSocketContext returns a context for the lifetime of a WebSocket connection
+// The connection outlives the upgrade request, so the context is detached from its cancellation
+// (see DetachContext) but keeps the request_id and correlation_id of the upgrade request.
+// Call it before Upgrade. For per-message tracing derive a child ID from it with NewChildRequestID,
+// messages then log their own request_id with the connection's as parent and a shared correlation_id.
+//
+// Usage:
+//
+//	connCtx := httputil.WebSocketContext(c)
+//	conn, err := upgrader.Upgrade(c.Writer, c.Request, httputil.WebSocketUpgradeHeader(c))
+//	if err != nil {
+//		return
+//	}
+//	defer conn.Close()
+//
+//	for {
+//		_, msg, err := conn.ReadMessage()
+//		if err != nil {
+//			return
+//		}
+//		_, msgCtx := httputil.NewChildRequestID(connCtx)
+//		h.handleMessage(msgCtx, msg)
+//	}
+func WebSocketContext(c *gin.Context) context.Context {
+	EnsureRequestID(c)
+	return DetachContext(ContextFromGin(c))
+}
@@ -0,0 +1,144 @@
+// Package otelutil connects request ID propagation with OpenTelemetry trace context
+// It is a separate package so services not using OpenTelemetry don't depend on it
+package otelutil
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+)
+
+// ContextFromGinWithTrace is httputil.ContextFromGin plus W3C trace context extraction
+// traceparent/tracestate are extracted with the global otel propagator,
+// which is a no-op until the service configures one via otel.SetTextMapPropagator.
+//
+// Usage:
+//
+//	ctx := otelutil.ContextFromGinWithTrace(c)
+//	result, err := h.service.DoSomething(ctx, params)
+func ContextFromGinWithTrace(c *gin.Context) context.Context {
+	ctx := httputil.ContextFromGin(c)
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(c.Request.Header))
+}
+
+// PropagateTraceContext adds request ID headers and W3C trace context headers to an outgoing request
+// Trace headers are only written when otel propagation is configured and ctx carries a span
+//
+// Usage:
+//
+//	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+//	otelutil.PropagateTraceContext(ctx, req)
+//	resp, err := client.Do(req)
+func PropagateTraceContext(ctx context.Context, req *http.Request) {
+	httputil.PropagateRequestIDFromContext(ctx, req)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+}
+
+// SpanRequestIDMiddleware tags the active span of the request with request_id and correlation_id attributes
+// Lets a trace found in the tracing backend be matched with log lines and with user reports quoting the ID.
+// Mount it after RequestIDMiddleware and the middleware starting the server span (e.g. otelgin).
+// Nothing happens for requests without a recording span.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddleware(), otelgin.Middleware("orders"), otelutil.SpanRequestIDMiddleware())
+func SpanRequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		TagSpan(c.Request.Context())
+		c.Next()
+	}
+}
+
+// TagSpan sets the request_id and correlation_id attributes of ctx on the span in ctx
+// Missing IDs are not generated.
+//
+// Usage:
+//
+//	ctx, span := tracer.Start(ctx, "process")
+//	defer span.End()
+//	otelutil.TagSpan(ctx)
+func TagSpan(ctx context.Context) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	if requestID, ok := httputil.RequestIDFromContext(ctx); ok {
+		span.SetAttributes(attribute.String(httputil.LogKeyRequestID, requestID))
+	}
+	if correlationID := httputil.GetCorrelationIDFromContext(ctx); correlationID != "" {
+		span.SetAttributes(attribute.String(httputil.LogKeyCorrelationID, correlationID))
+	}
+}
+
+// WithSpanAttributes makes the request ID middleware of httputil.Middlewares tag the active span
+// Like SpanRequestIDMiddleware, but as an option of the middleware stack. The span must already be in
+// the request context, so start it in front of the stack (otelhttp wrapping the router, or otelgin
+// mounted before the stack). For RequestIDMiddlewareWithConfig add ContextWithSpanAttributes to
+// Config.ContextHooks instead.
+//
+// Usage:
+//
+//	router.Use(otelgin.Middleware("orders"))
+//	router.Use(httputil.Middlewares(
+//		httputil.WithLogger(logger),
+//		otelutil.WithSpanAttributes(),
+//	)...)
+func WithSpanAttributes() httputil.Option {
+	return httputil.WithContextHook(ContextWithSpanAttributes)
+}
+
+// ContextWithSpanAttributes is TagSpan as a context hook, ctx is returned unchanged
+func ContextWithSpanAttributes(ctx context.Context) context.Context {
+	TagSpan(ctx)
+	return ctx
+}
+
+// BaggageKeyRequestID is the OpenTelemetry baggage member carrying the request ID
+const BaggageKeyRequestID = httputil.LogKeyRequestID
+
+// WithOtelBaggage makes the request ID middleware of httputil.Middlewares mirror request_id into otel baggage
+// Span processors copying baggage to attributes then tag every span with it, and otel propagators
+// forward it downstream. For RequestIDMiddlewareWithConfig add ContextWithRequestIDBaggage to
+// Config.ContextHooks instead.
+//
+// Usage:
+//
+//	router.Use(httputil.Middlewares(
+//		httputil.WithLogger(logger),
+//		otelutil.WithOtelBaggage(),
+//	)...)
+func WithOtelBaggage() httputil.Option {
+	return httputil.WithContextHook(ContextWithRequestIDBaggage)
+}
+
+// ContextWithRequestIDBaggage sets the request ID of ctx as the request_id otel baggage member
+// Other baggage members are kept. ctx is returned unchanged if it has no request ID.
+//
+// Usage:
+//
+//	router.Use(httputil.RequestIDMiddlewareWithConfig(httputil.Config{
+//		ContextHooks: []func(context.Context) context.Context{otelutil.ContextWithRequestIDBaggage},
+//	}))
+func ContextWithRequestIDBaggage(ctx context.Context) context.Context {
+	requestID, ok := httputil.RequestIDFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	member, err := baggage.NewMemberRaw(BaggageKeyRequestID, requestID)
+	if err != nil {
+		return ctx
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
@@ -0,0 +1,96 @@
+// Package promutil provides Prometheus metrics middleware for gin
+// It is a separate package so services not using Prometheus don't depend on it
+package promutil
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+)
+
+// unmatchedRoute is the route label for requests that matched no gin route
+const unmatchedRoute = "unmatched"
+
+// Metrics holds HTTP request collectors labeled by method, route and status
+// Collectors are not registered automatically, register them with your own registry
+type Metrics struct {
+	// Requests counts handled requests
+	Requests *prometheus.CounterVec
+
+	// Latency observes request duration in seconds
+	Latency *prometheus.HistogramVec
+
+	// Clock measures latency, httputil.SystemClock is used if nil
+	Clock httputil.Clock
+}
+
+// DefaultMetrics is used by MetricsMiddleware
+var DefaultMetrics = NewMetrics("http")
+
+// NewMetrics creates request collectors with the given metric namespace
+func NewMetrics(namespace string) *Metrics {
+	labels := []string{"method", "route", "status"}
+	return &Metrics{
+		Requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Total number of HTTP requests.",
+		}, labels),
+		Latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+}
+
+// Collectors returns all collectors for registration
+//
+// Usage:
+//
+//	registry.MustRegister(promutil.DefaultMetrics.Collectors()...)
+func (m *Metrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.Requests, m.Latency}
+}
+
+// Middleware records request count and latency
+// The route label is the matched gin route template (c.FullPath()), not the raw path,
+// so /users/:id produces a single series instead of one per user.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	clock := m.Clock
+	if clock == nil {
+		clock = httputil.SystemClock{}
+	}
+
+	return func(c *gin.Context) {
+		start := clock.Now()
+		c.Next()
+		latency := clock.Now().Sub(start)
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		labels := prometheus.Labels{
+			"method": c.Request.Method,
+			"route":  route,
+			"status": strconv.Itoa(c.Writer.Status()),
+		}
+		m.Requests.With(labels).Inc()
+		m.Latency.With(labels).Observe(latency.Seconds())
+	}
+}
+
+// MetricsMiddleware records request metrics into DefaultMetrics
+//
+// Usage:
+//
+//	prometheus.MustRegister(promutil.DefaultMetrics.Collectors()...)
+//	router.Use(promutil.MetricsMiddleware())
+func MetricsMiddleware() gin.HandlerFunc {
+	return DefaultMetrics.Middleware()
+}
@@ -0,0 +1,25 @@
+package reqctx
+
+import "context"
+
+// HeaderAPIVersion carries the API contract version the call chain must honor
+const HeaderAPIVersion = "X-API-Version"
+
+// apiVersionValue holds the API version
+var apiVersionValue = NewContextValue[string]("api_version")
+
+// ContextWithAPIVersion creates a new context with the API version
+func ContextWithAPIVersion(ctx context.Context, version string) context.Context {
+	return apiVersionValue.With(ctx, version)
+}
+
+// APIVersionFromContext returns the API version and whether it was found
+//
+// Usage:
+//
+//	if version, _ := reqctx.APIVersionFromContext(ctx); version == "2024-01-01" {
+//		return legacyResponse(order), nil
+//	}
+func APIVersionFromContext(ctx context.Context) (string, bool) {
+	return apiVersionValue.Get(ctx)
+}
@@ -0,0 +1,158 @@
+package reqctx
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// HeaderBaggage is the W3C baggage header
+const HeaderBaggage = "Baggage"
+
+// MaxBaggageSize is the maximum size in bytes of the encoded Baggage header
+// Members that don't fit are dropped on propagation, larger incoming headers are ignored
+const MaxBaggageSize = 8192
+
+// baggageKey is the context key for baggage members
+const baggageKey contextKey = "baggage"
+
+// ContextWithBaggage creates a new context with an additional baggage member
+// Baggage carries small key/value pairs (feature-flag overrides, experiment bucket) across hops.
+// Keys must be valid HTTP tokens, invalid keys are ignored.
+//
+// Usage:
+//
+//	ctx = reqctx.ContextWithBaggage(ctx, "experiment", "checkout-v2")
+func ContextWithBaggage(ctx context.Context, key, value string) context.Context {
+	if !isToken(key) {
+		return ctx
+	}
+
+	current := baggageFromContext(ctx)
+	members := make(map[string]string, len(current)+1)
+	for k, v := range current {
+		members[k] = v
+	}
+	members[key] = value
+	return context.WithValue(ctx, baggageKey, members)
+}
+
+// BaggageFromContext returns a copy of the baggage members stored in ctx
+func BaggageFromContext(ctx context.Context) map[string]string {
+	current := baggageFromContext(ctx)
+	members := make(map[string]string, len(current))
+	for k, v := range current {
+		members[k] = v
+	}
+	return members
+}
+
+// baggageFromContext returns the stored members without copying, callers must not modify them
+func baggageFromContext(ctx context.Context) map[string]string {
+	members, _ := ctx.Value(baggageKey).(map[string]string)
+	return members
+}
+
+// EncodeBaggage renders members in W3C baggage format, dropping members beyond MaxBaggageSize
+// Keys are sorted so the header is deterministic
+func EncodeBaggage(members map[string]string) string {
+	keys := make([]string, 0, len(members))
+	for k := range members {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		member := k + "=" + escapeBaggageValue(members[k])
+		size := len(member)
+		if b.Len() > 0 {
+			size++
+		}
+		if b.Len()+size > MaxBaggageSize {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(member)
+	}
+	return b.String()
+}
+
+// ContextWithBaggageHeader merges the members of a W3C Baggage header into ctx
+// Member properties are ignored, malformed members are skipped, headers over MaxBaggageSize are ignored
+func ContextWithBaggageHeader(ctx context.Context, header string) context.Context {
+	if header == "" || len(header) > MaxBaggageSize {
+		return ctx
+	}
+
+	members := make(map[string]string)
+	for _, member := range strings.Split(header, ",") {
+		member, _, _ = strings.Cut(member, ";")
+		key, value, ok := strings.Cut(member, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isToken(key) {
+			continue
+		}
+		value, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		members[key] = value
+	}
+	return contextWithBaggageMembers(ctx, members)
+}
+
+// contextWithBaggageMembers merges members over the baggage already stored in ctx
+func contextWithBaggageMembers(ctx context.Context, members map[string]string) context.Context {
+	if len(members) == 0 {
+		return ctx
+	}
+
+	current := baggageFromContext(ctx)
+	merged := make(map[string]string, len(current)+len(members))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range members {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, baggageKey, merged)
+}
+
+// escapeBaggageValue percent-encodes characters not allowed in a W3C baggage value
+func escapeBaggageValue(v string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c > 0x20 && c < 0x7f && c != '"' && c != ',' && c != ';' && c != '\\' && c != '%' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0x0f])
+	}
+	return b.String()
+}
+
+// isToken reports whether s is a non-empty RFC 7230 token
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
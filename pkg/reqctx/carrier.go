@@ -0,0 +1,175 @@
+package reqctx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Carrier receives tracing identifiers from Inject, one Set call per header
+// Keys are the HTTP header names, e.g. X-Request-ID. Adapters for a transport lowercase or
+// otherwise map them as the transport requires.
+type Carrier interface {
+	Set(key, value string)
+}
+
+// Extractor provides tracing identifiers to Extract
+// Get must return an empty string for missing keys.
+type Extractor interface {
+	Get(key string) string
+}
+
+// CarrierFunc adapts a function to Carrier
+type CarrierFunc func(key, value string)
+
+// Set implements Carrier
+func (f CarrierFunc) Set(key, value string) {
+	f(key, value)
+}
+
+// ExtractorFunc adapts a function to Extractor
+type ExtractorFunc func(key string) string
+
+// Get implements Extractor
+func (f ExtractorFunc) Get(key string) string {
+	return f(key)
+}
+
+// HeaderCarrier adapts http.Header to Carrier and Extractor, keys are canonicalized by http.Header
+type HeaderCarrier http.Header
+
+// Set implements Carrier
+func (h HeaderCarrier) Set(key, value string) {
+	http.Header(h).Set(key, value)
+}
+
+// Get implements Extractor
+func (h HeaderCarrier) Get(key string) string {
+	return http.Header(h).Get(key)
+}
+
+// MapCarrier adapts a map to Carrier and Extractor, keys are used as given
+type MapCarrier map[string]string
+
+// Set implements Carrier
+func (m MapCarrier) Set(key, value string) {
+	m[key] = value
+}
+
+// Get implements Extractor
+func (m MapCarrier) Get(key string) string {
+	return m[key]
+}
+
+// Inject writes the tracing identifiers of ctx to carrier
+// The set of identifiers is the one of TracingHeadersFromContext. A request ID is generated if ctx has none.
+//
+// Usage:
+//
+//	reqctx.Inject(ctx, reqctx.CarrierFunc(func(key, value string) {
+//		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+//	}))
+func Inject(ctx context.Context, carrier Carrier) {
+	for key, value := range TracingHeadersFromContext(ctx) {
+		carrier.Set(key, value)
+	}
+}
+
+// Extract builds a background context from the tracing identifiers of an untrusted carrier
+// It is ExtractContext(context.Background(), carrier).
+//
+// Usage:
+//
+//	ctx := reqctx.Extract(reqctx.HeaderCarrier(req.Header))
+func Extract(carrier Extractor) context.Context {
+	return ExtractContext(context.Background(), carrier)
+}
+
+// ExtractOptions selects the carrier values honored by ExtractContextWithOptions
+type ExtractOptions struct {
+	// Trusted honors values a caller could set to gain service it isn't entitled to:
+	// X-Trace-Sampled, X-Request-Priority, X-Client-IP and X-Risk-Score. Set it only for carriers from peers
+	// that set or verify these values themselves, e.g. an internal gateway. Untrusted carriers
+	// are treated like HTTP requests from outside Config.TrustedProxies in httputil.
+	Trusted bool
+
+	// HonorDryRun reads X-Dry-Run of trusted carriers, see DryRunFromContext
+	// Off by default like httputil's Config.HonorDryRun: a dry run skips side effects.
+	HonorDryRun bool
+}
+
+// ExtractContext stores the tracing identifiers of carrier in ctx, treating the carrier as untrusted
+// Like the HTTP middlewares, a missing or invalid request ID is generated and the correlation ID
+// defaults to the request ID. Malformed optional values (tenant, locale, ...) are dropped, the
+// values gated by ExtractOptions.Trusted are ignored. See ExtractContextWithOptions.
+func ExtractContext(ctx context.Context, carrier Extractor) context.Context {
+	return ExtractContextWithOptions(ctx, carrier, ExtractOptions{})
+}
+
+// ExtractContextWithOptions is ExtractContext honoring the carrier values opts allows
+//
+// Usage:
+//
+//	// a consumer of a queue only internal services publish to
+//	ctx := reqctx.ExtractContextWithOptions(ctx, carrier, reqctx.ExtractOptions{Trusted: true})
+func ExtractContextWithOptions(ctx context.Context, carrier Extractor, opts ExtractOptions) context.Context {
+	correlationID := trustedValue(carrier.Get(HeaderCorrelationID))
+
+	requestID := trustedValue(carrier.Get(HeaderRequestID))
+	if requestID == "" {
+		requestID = NewRequestID()
+	}
+	if correlationID == "" {
+		correlationID = requestID
+	}
+
+	values := TracingValues{
+		RequestID:       requestID,
+		CorrelationID:   correlationID,
+		ParentRequestID: trustedValue(carrier.Get(HeaderParentRequestID)),
+	}
+	if tenantID := carrier.Get(HeaderTenantID); tenantID != "" && ValidateTenantID(tenantID) == nil {
+		values.TenantID = tenantID
+	}
+	ctx = ContextWithTracing(ctx, values)
+	if key := carrier.Get(HeaderIdempotencyKey); ValidateIdempotencyKey(key) == nil {
+		ctx = ContextWithIdempotencyKey(ctx, key)
+	}
+	if opts.Trusted {
+		if sampled, ok := ParseSampled(carrier.Get(HeaderTraceSampled)); ok {
+			ctx = ContextWithSampled(ctx, sampled)
+		}
+		if priority, ok := ParsePriority(carrier.Get(HeaderRequestPriority)); ok {
+			ctx = ContextWithPriority(ctx, priority)
+		}
+		if ip, ok := ParseClientIP(strings.TrimSpace(carrier.Get(HeaderClientIP))); ok {
+			ctx = ContextWithClientIP(ctx, ip)
+		}
+		ctx = ContextWithRiskScoreHeader(ctx, carrier.Get(HeaderRiskScore))
+	}
+	if tag, ok := ParseLocale(carrier.Get(HeaderRequestLocale)); ok {
+		ctx = ContextWithLocale(ctx, tag)
+	}
+	if version := trustedValue(carrier.Get(HeaderAPIVersion)); version != "" {
+		ctx = ContextWithAPIVersion(ctx, version)
+	}
+	if opts.Trusted && opts.HonorDryRun && ParseDryRun(carrier.Get(HeaderDryRun)) {
+		ctx = ContextWithDryRun(ctx, true)
+	}
+	if seq, ok := ParseSequence(carrier.Get(HeaderTraceSequence)); ok {
+		ctx = ContextWithSequence(ctx, seq)
+	}
+	if hops, ok := ParseHopCount(carrier.Get(HeaderRequestHops)); ok {
+		ctx = ContextWithHopCount(ctx, hops)
+	}
+	return ContextWithBaggageHeader(ctx, carrier.Get(HeaderBaggage))
+}
+
+// trustedValue returns the trimmed value if it passes ValidateRequestID, otherwise empty string
+func trustedValue(value string) string {
+	value = strings.TrimSpace(value)
+	if ValidateRequestID(value) != nil {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,89 @@
+package reqctx
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestExtractContextWithOptions(t *testing.T) {
+	carrier := MapCarrier{
+		HeaderRequestID:       "req-1",
+		HeaderCorrelationID:   "corr-1",
+		HeaderParentRequestID: "parent-1",
+		HeaderTraceSampled:    "0",
+		HeaderRequestPriority: "high",
+		HeaderClientIP:        "203.0.113.7",
+		HeaderRiskScore:       "0",
+	}
+
+	for _, tt := range []struct {
+		name    string
+		extract func() context.Context
+		trusted bool
+	}{
+		{name: "ExtractContext", extract: func() context.Context { return ExtractContext(context.Background(), carrier) }},
+		{name: "Extract", extract: func() context.Context { return Extract(carrier) }},
+		{name: "ExtractFormats", extract: func() context.Context { return ExtractFormats(context.Background(), carrier, NativeFormat) }},
+		{name: "untrusted", extract: func() context.Context {
+			return ExtractContextWithOptions(context.Background(), carrier, ExtractOptions{})
+		}},
+		{name: "trusted", trusted: true, extract: func() context.Context {
+			return ExtractContextWithOptions(context.Background(), carrier, ExtractOptions{Trusted: true})
+		}},
+		{name: "trusted formats", trusted: true, extract: func() context.Context {
+			return ExtractFormatsWithOptions(context.Background(), carrier, ExtractOptions{Trusted: true}, NativeFormat)
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.extract()
+
+			// the IDs are honored either way
+			if id, _ := RequestIDFromContext(ctx); id != "req-1" {
+				t.Errorf("request ID = %q, want req-1", id)
+			}
+			if id := GetCorrelationIDFromContext(ctx); id != "corr-1" {
+				t.Errorf("correlation ID = %q, want corr-1", id)
+			}
+			if id, _ := ParentRequestIDFromContext(ctx); id != "parent-1" {
+				t.Errorf("parent request ID = %q, want parent-1", id)
+			}
+
+			if sampled := !IsSampled(ctx); sampled != tt.trusted {
+				t.Errorf("sampling decision honored = %v, want %v", sampled, tt.trusted)
+			}
+			if high := PriorityFromContext(ctx) == PriorityHigh; high != tt.trusted {
+				t.Errorf("priority honored = %v, want %v", high, tt.trusted)
+			}
+			if _, ok := ClientIPFromContext(ctx); ok != tt.trusted {
+				t.Errorf("client IP honored = %v, want %v", ok, tt.trusted)
+			}
+			if _, ok := RiskScoreFromContext(ctx); ok != tt.trusted {
+				t.Errorf("risk score honored = %v, want %v", ok, tt.trusted)
+			}
+		})
+	}
+}
+
+func TestExtractContextDryRun(t *testing.T) {
+	carrier := MapCarrier{HeaderDryRun: "true"}
+
+	for _, tt := range []struct {
+		opts ExtractOptions
+		want bool
+	}{
+		{opts: ExtractOptions{}, want: false},
+		{opts: ExtractOptions{HonorDryRun: true}, want: false},
+		{opts: ExtractOptions{Trusted: true}, want: false},
+		{opts: ExtractOptions{Trusted: true, HonorDryRun: true}, want: true},
+	} {
+		t.Run(fmt.Sprintf("%+v", tt.opts), func(t *testing.T) {
+			if got := DryRunFromContext(ExtractContextWithOptions(context.Background(), carrier, tt.opts)); got != tt.want {
+				t.Errorf("dry run = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if DryRunFromContext(Extract(carrier)) {
+		t.Error("Extract honored X-Dry-Run")
+	}
+}
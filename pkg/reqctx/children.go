@@ -0,0 +1,86 @@
+package reqctx
+
+import (
+	"context"
+	"sync"
+)
+
+// MaxChildRequestIDs bounds the child request IDs collected per request
+// IDs beyond it are counted but not kept, so pathological fan-out can't grow memory unbounded.
+const MaxChildRequestIDs = 100
+
+// childCollectorKey is the context key for the child request ID collector
+const childCollectorKey contextKey = "child_request_ids"
+
+// childCollector records the request IDs of downstream calls, shared by all contexts derived from a request
+type childCollector struct {
+	mu      sync.Mutex
+	owner   string
+	ids     []string
+	seen    map[string]struct{}
+	dropped int
+}
+
+// ContextWithChildCollector makes NewChildRequestID and the propagating transports record
+// downstream request IDs in ctx, see ChildRequestIDsFromContext
+// The current request ID of ctx is never recorded as its own child. A collector already in ctx is kept.
+func ContextWithChildCollector(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(childCollectorKey).(*childCollector); ok {
+		return ctx
+	}
+	owner, _ := RequestIDFromContext(ctx)
+	return context.WithValue(ctx, childCollectorKey, &childCollector{owner: owner, seen: map[string]struct{}{}})
+}
+
+// RecordChildRequestID adds id to the collector of ctx, a no-op without ContextWithChildCollector
+// Duplicates and the request ID of the collecting request itself are skipped. Safe for concurrent use.
+func RecordChildRequestID(ctx context.Context, id string) {
+	collector, ok := ctx.Value(childCollectorKey).(*childCollector)
+	if !ok || id == "" {
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if id == collector.owner {
+		return
+	}
+	if _, dup := collector.seen[id]; dup {
+		return
+	}
+	if len(collector.ids) >= MaxChildRequestIDs {
+		collector.dropped++
+		return
+	}
+	collector.seen[id] = struct{}{}
+	collector.ids = append(collector.ids, id)
+}
+
+// ChildRequestIDsFromContext returns the downstream request IDs recorded so far, in call order
+// Nil without ContextWithChildCollector. At most MaxChildRequestIDs are returned, see DroppedChildRequestIDs.
+//
+// Usage:
+//
+//	log.Info("request done", "child_request_ids", reqctx.ChildRequestIDsFromContext(ctx))
+func ChildRequestIDsFromContext(ctx context.Context) []string {
+	collector, ok := ctx.Value(childCollectorKey).(*childCollector)
+	if !ok {
+		return nil
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	return append([]string(nil), collector.ids...)
+}
+
+// DroppedChildRequestIDs returns how many child request IDs were not kept because of MaxChildRequestIDs
+func DroppedChildRequestIDs(ctx context.Context) int {
+	collector, ok := ctx.Value(childCollectorKey).(*childCollector)
+	if !ok {
+		return 0
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	return collector.dropped
+}
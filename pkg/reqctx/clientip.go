@@ -0,0 +1,38 @@
+package reqctx
+
+import (
+	"context"
+	"net/netip"
+)
+
+// HeaderClientIP carries the original client IP across internal hops
+const HeaderClientIP = "X-Client-IP"
+
+// clientIPValue holds the original client IP
+var clientIPValue = NewContextValue[string]("client_ip")
+
+// ContextWithClientIP creates a new context with the original client IP
+func ContextWithClientIP(ctx context.Context, ip string) context.Context {
+	return clientIPValue.With(ctx, ip)
+}
+
+// ClientIPFromContext returns the original client IP and whether it was set
+//
+// Usage:
+//
+//	if ip, ok := reqctx.ClientIPFromContext(ctx); ok {
+//		audit.Log("login failed", "client_ip", ip, "request_id", reqctx.GetRequestIDFromContext(ctx))
+//	}
+func ClientIPFromContext(ctx context.Context) (string, bool) {
+	return clientIPValue.Get(ctx)
+}
+
+// ParseClientIP returns ip in canonical form, ok is false if it is not an IP address
+// IPv4-mapped IPv6 addresses are unmapped, so one client always has the same representation.
+func ParseClientIP(ip string) (string, bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "", false
+	}
+	return addr.Unmap().String(), true
+}
@@ -0,0 +1,185 @@
+// Package reqctx is the gin-free core of httputil: request tracing values in context.Context,
+// request ID generation and the headers propagating them downstream.
+// Background workers and gRPC services can depend on it without pulling gin into the binary.
+// httputil re-exports everything here, values set through either package are visible to both.
+// A *gin.Context doesn't expose these values, pass c.Request.Context() or use httputil.
+package reqctx
+
+import (
+	"context"
+	"net/http"
+)
+
+// contextKey is a custom type for context keys to avoid collisions
+type contextKey string
+
+const (
+	// HeaderRequestID is the standard request ID header
+	HeaderRequestID = "X-Request-ID"
+
+	// HeaderCorrelationID is the correlation ID header for distributed tracing
+	HeaderCorrelationID = "X-Correlation-ID"
+)
+
+const (
+	// LogKeyRequestID is the log attribute key for request ID
+	LogKeyRequestID = "request_id"
+
+	// LogKeyCorrelationID is the log attribute key for correlation ID
+	LogKeyCorrelationID = "correlation_id"
+)
+
+// ContextWithRequestID creates a new context with request_id value
+// Useful for passing request ID to goroutines or async operations
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	values := tracingValue.Value(ctx)
+	values.RequestID = requestID
+	return tracingValue.With(ctx, values)
+}
+
+// ContextWithCorrelationID creates a new context with correlation_id value
+// The correlation ID spans the whole transaction while the request ID identifies a single hop
+func ContextWithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	values := tracingValue.Value(ctx)
+	values.CorrelationID = correlationID
+	return tracingValue.With(ctx, values)
+}
+
+// GetRequestIDFromContext extracts request_id from context.Context
+// Note: if no request ID is stored, a NEW ID is generated on every call, so two calls
+// on the same context may return different values. Use EnsureRequestID to generate once
+// and remember, or RequestIDFromContext to detect a missing ID.
+func GetRequestIDFromContext(ctx context.Context) string {
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		return requestID
+	}
+	return NewRequestID()
+}
+
+// RequestIDFromContext returns the stored request_id and whether it was found
+// Unlike GetRequestIDFromContext it never generates a new ID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID := tracingValue.Value(ctx).RequestID
+	return requestID, requestID != ""
+}
+
+// EnsureRequestID returns the request ID from ctx, generating and storing one if missing
+// All reads from the returned context yield the same ID.
+//
+// Usage:
+//
+//	requestID, ctx := reqctx.EnsureRequestID(ctx)
+//	log.Info("bootstrap started", "request_id", requestID)
+func EnsureRequestID(ctx context.Context) (string, context.Context) {
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		return requestID, ctx
+	}
+
+	requestID := NewRequestID()
+	return requestID, ContextWithRequestID(ctx, requestID)
+}
+
+// GetCorrelationIDFromContext extracts correlation_id from context.Context
+// Returns empty string if no correlation ID is set
+func GetCorrelationIDFromContext(ctx context.Context) string {
+	return tracingValue.Value(ctx).CorrelationID
+}
+
+// PropagateRequestIDFromContext adds tracing headers from context.Context to req
+// X-Correlation-ID carries the correlation ID if set, otherwise the request ID.
+// X-Parent-Request-ID is sent for contexts from NewChildRequestID,
+// Idempotency-Key for contexts from ContextWithIdempotencyKey, X-Tenant-ID for ContextWithTenantID.
+// Baggage members from ContextWithBaggage are sent in the Baggage header,
+// the sampling decision of ContextWithSampled in X-Trace-Sampled,
+// the priority of ContextWithPriority in X-Request-Priority, the locale of ContextWithLocale in X-Request-Locale,
+// the client IP of ContextWithClientIP in X-Client-IP, the score of ContextWithRiskScore in X-Risk-Score,
+// the version of ContextWithAPIVersion in X-API-Version, X-Dry-Run for dry runs, the next hop number
+// in X-Trace-Sequence and the next hop count in X-Request-Hops.
+// Propagation ignores cancellation, headers are stamped even if ctx is already done.
+//
+// Usage:
+//
+//	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+//	reqctx.PropagateRequestIDFromContext(ctx, req)
+//	resp, err := client.Do(req)
+func PropagateRequestIDFromContext(ctx context.Context, req *http.Request) {
+	Inject(ctx, HeaderCarrier(req.Header))
+}
+
+// TracingHeaderNames returns the names of all headers TracingHeadersFromContext may send
+// For allow-lists of gateways and proxies that drop unknown headers, e.g. grpcutil.GatewayTracingMetadata.
+func TracingHeaderNames() []string {
+	return []string{
+		HeaderRequestID,
+		HeaderCorrelationID,
+		HeaderParentRequestID,
+		HeaderIdempotencyKey,
+		HeaderBaggage,
+		HeaderTenantID,
+		HeaderTraceSampled,
+		HeaderRequestPriority,
+		HeaderRequestLocale,
+		HeaderClientIP,
+		HeaderRiskScore,
+		HeaderAPIVersion,
+		HeaderDryRun,
+		HeaderTraceSequence,
+		HeaderRequestHops,
+	}
+}
+
+// TracingHeadersFromContext returns the tracing headers from context.Context as a map
+// This is the single place defining what gets propagated. A request ID is generated if ctx has none.
+//
+// Usage:
+//
+//	headers := reqctx.TracingHeadersFromContext(ctx)
+//	sdkClient.Call(ctx, params, sdk.WithHeaders(headers))
+func TracingHeadersFromContext(ctx context.Context) map[string]string {
+	requestID := GetRequestIDFromContext(ctx)
+	correlationID := GetCorrelationIDFromContext(ctx)
+	if correlationID == "" {
+		correlationID = requestID
+	}
+
+	headers := map[string]string{
+		HeaderRequestID:     requestID,
+		HeaderCorrelationID: correlationHeaderValue(ctx, correlationID),
+	}
+	if parentID, ok := ParentRequestIDFromContext(ctx); ok {
+		headers[HeaderParentRequestID] = parentID
+	}
+	if key, ok := IdempotencyKeyFromContext(ctx); ok {
+		headers[HeaderIdempotencyKey] = key
+	}
+	if baggage := EncodeBaggage(baggageFromContext(ctx)); baggage != "" {
+		headers[HeaderBaggage] = baggage
+	}
+	if tenantID, ok := TenantIDFromContext(ctx); ok {
+		headers[HeaderTenantID] = tenantID
+	}
+	if sampled := sampledHeaderValue(ctx); sampled != "" {
+		headers[HeaderTraceSampled] = sampled
+	}
+	if priority := priorityHeaderValue(ctx); priority != "" {
+		headers[HeaderRequestPriority] = priority
+	}
+	if tag, ok := LocaleFromContext(ctx); ok {
+		headers[HeaderRequestLocale] = tag.String()
+	}
+	if ip, ok := ClientIPFromContext(ctx); ok {
+		headers[HeaderClientIP] = ip
+	}
+	if score := riskScoreHeaderValue(ctx); score != "" {
+		headers[HeaderRiskScore] = score
+	}
+	if version, ok := APIVersionFromContext(ctx); ok {
+		headers[HeaderAPIVersion] = version
+	}
+	if DryRunFromContext(ctx) {
+		headers[HeaderDryRun] = "true"
+	}
+	headers[HeaderTraceSequence] = sequenceHeaderValue(ctx)
+	headers[HeaderRequestHops] = hopsHeaderValue(ctx)
+	return headers
+}
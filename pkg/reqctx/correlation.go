@@ -0,0 +1,65 @@
+package reqctx
+
+import (
+	"context"
+	"strings"
+)
+
+// additionalCorrelationIDsKey is the context key for correlation IDs joined into the request
+const additionalCorrelationIDsKey contextKey = "additional_correlation_ids"
+
+// ContextWithAdditionalCorrelationID records one more correlation ID for requests joining several traces
+// The primary correlation ID stays first. Propagation sends all of them comma-separated in
+// X-Correlation-ID, so services unaware of additional IDs keep reading the primary one.
+// Invalid IDs, IDs containing commas and duplicates are ignored.
+//
+// Usage:
+//
+//	for _, part := range batch {
+//		ctx = reqctx.ContextWithAdditionalCorrelationID(ctx, part.CorrelationID)
+//	}
+func ContextWithAdditionalCorrelationID(ctx context.Context, correlationID string) context.Context {
+	if ValidateRequestID(correlationID) != nil || strings.Contains(correlationID, ",") {
+		return ctx
+	}
+	for _, id := range CorrelationIDsFromContext(ctx) {
+		if id == correlationID {
+			return ctx
+		}
+	}
+
+	current := additionalCorrelationIDs(ctx)
+	ids := make([]string, len(current), len(current)+1)
+	copy(ids, current)
+	return context.WithValue(ctx, additionalCorrelationIDsKey, append(ids, correlationID))
+}
+
+// CorrelationIDsFromContext returns the primary correlation ID followed by the additional ones
+// Returns nil if ctx has no correlation ID at all
+func CorrelationIDsFromContext(ctx context.Context) []string {
+	additional := additionalCorrelationIDs(ctx)
+	ids := make([]string, 0, len(additional)+1)
+	if correlationID := GetCorrelationIDFromContext(ctx); correlationID != "" {
+		ids = append(ids, correlationID)
+	}
+	ids = append(ids, additional...)
+	if len(ids) == 0 {
+		return nil
+	}
+	return ids
+}
+
+// additionalCorrelationIDs returns the stored additional IDs without copying, callers must not modify them
+func additionalCorrelationIDs(ctx context.Context) []string {
+	ids, _ := ctx.Value(additionalCorrelationIDsKey).([]string)
+	return ids
+}
+
+// correlationHeaderValue joins the primary correlation ID with the additional ones stored in ctx
+func correlationHeaderValue(ctx context.Context, correlationID string) string {
+	additional := additionalCorrelationIDs(ctx)
+	if len(additional) == 0 {
+		return correlationID
+	}
+	return strings.Join(append([]string{correlationID}, additional...), ",")
+}
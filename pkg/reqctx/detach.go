@@ -0,0 +1,87 @@
+package reqctx
+
+import "context"
+
+// DetachContext returns a background-rooted context carrying only request_id and correlation_id from ctx
+// Cancellation and deadline of ctx are not inherited, so async work started from a request
+// survives the request's completion but keeps its trace identifiers.
+//
+// Usage:
+//
+//	go h.notifier.Send(reqctx.DetachContext(ctx), order)
+func DetachContext(ctx context.Context) context.Context {
+	detached := context.Background()
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		detached = ContextWithRequestID(detached, requestID)
+	}
+	if correlationID := GetCorrelationIDFromContext(ctx); correlationID != "" {
+		detached = ContextWithCorrelationID(detached, correlationID)
+	}
+	return detached
+}
+
+// CancelableDetached returns a detached context with the tracing values of ctx and its own cancel func
+// Unlike DetachContext, which keeps only request_id and correlation_id and can't be cancelled,
+// it carries everything WithTracingFrom copies and is stopped by the returned cancel, never by ctx.
+// For request-initiated background tasks whose lifetime is managed explicitly; always call cancel.
+//
+// Usage:
+//
+//	ctx, cancel := reqctx.CancelableDetached(ctx)
+//	s.exports.Store(exportID, cancel)
+//	go s.runExport(ctx, exportID)
+func CancelableDetached(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithCancel(WithTracingFrom(context.Background(), ctx))
+}
+
+// WithTracingFrom copies request_id, correlation_id, tenant_id, baggage, the sampling decision, priority,
+// locale, client IP, risk score, API version, the dry-run flag, trace sequence and hop count from src onto dst
+// Cancellation and deadline of src are not copied. Identifiers missing in src leave dst unchanged,
+// baggage members from src are merged over those of dst.
+//
+// Usage:
+//
+//	for job := range jobs {
+//		ctx := reqctx.WithTracingFrom(workerCtx, job.Context)
+//		process(ctx, job)
+//	}
+func WithTracingFrom(dst, src context.Context) context.Context {
+	if values := TracingFromContext(src); values.RequestID != "" || values.CorrelationID != "" || values.TenantID != "" {
+		dst = ContextWithTracing(dst, TracingValues{
+			RequestID:     values.RequestID,
+			CorrelationID: values.CorrelationID,
+			TenantID:      values.TenantID,
+		})
+	}
+	if members := baggageFromContext(src); len(members) > 0 {
+		dst = contextWithBaggageMembers(dst, members)
+	}
+	if sampled, ok := sampledValue.Get(src); ok {
+		dst = ContextWithSampled(dst, sampled)
+	}
+	if priority, ok := priorityValue.Get(src); ok {
+		dst = ContextWithPriority(dst, priority)
+	}
+	if tag, ok := LocaleFromContext(src); ok {
+		dst = ContextWithLocale(dst, tag)
+	}
+	if ip, ok := ClientIPFromContext(src); ok {
+		dst = ContextWithClientIP(dst, ip)
+	}
+	if score, ok := riskScoreValue.Get(src); ok {
+		dst = ContextWithRiskScore(dst, score)
+	}
+	if version, ok := APIVersionFromContext(src); ok {
+		dst = ContextWithAPIVersion(dst, version)
+	}
+	if dryRun, ok := dryRunValue.Get(src); ok {
+		dst = ContextWithDryRun(dst, dryRun)
+	}
+	if seq, ok := sequenceValue.Get(src); ok {
+		dst = ContextWithSequence(dst, seq)
+	}
+	if hops, ok := hopsValue.Get(src); ok {
+		dst = ContextWithHopCount(dst, hops)
+	}
+	return dst
+}
@@ -0,0 +1,35 @@
+package reqctx
+
+import (
+	"context"
+	"strings"
+)
+
+// HeaderDryRun marks a request whose whole call chain must skip side effects, "true" or "1"
+const HeaderDryRun = "X-Dry-Run"
+
+// dryRunValue holds the dry-run flag
+var dryRunValue = NewContextValue[bool]("dry_run")
+
+// ContextWithDryRun creates a new context with the dry-run flag
+func ContextWithDryRun(ctx context.Context, dryRun bool) context.Context {
+	return dryRunValue.With(ctx, dryRun)
+}
+
+// DryRunFromContext reports whether the request is a dry run, false if no flag is set
+//
+// Usage:
+//
+//	if reqctx.DryRunFromContext(ctx) {
+//		return order, nil
+//	}
+//	return order, s.repo.Save(ctx, order)
+func DryRunFromContext(ctx context.Context) bool {
+	return dryRunValue.Value(ctx)
+}
+
+// ParseDryRun parses an X-Dry-Run value, only "true" (any case) and "1" enable dry-run mode
+func ParseDryRun(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "1" || strings.EqualFold(value, "true")
+}
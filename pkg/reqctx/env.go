@@ -0,0 +1,62 @@
+package reqctx
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// EnvRequestID is the environment variable carrying the request ID into subprocesses
+	EnvRequestID = "REQUEST_ID"
+
+	// EnvCorrelationID is the environment variable carrying the correlation ID into subprocesses
+	EnvCorrelationID = "CORRELATION_ID"
+)
+
+// PropagateToEnv appends REQUEST_ID and CORRELATION_ID from ctx to the environment of cmd
+// A nil cmd.Env is first filled with os.Environ(), so the child keeps inheriting the environment.
+// Like outgoing headers, the correlation ID defaults to the request ID and a request ID is generated
+// if ctx has none. Call it before cmd.Start.
+//
+// Usage:
+//
+//	cmd := exec.CommandContext(ctx, "convert", in, out)
+//	reqctx.PropagateToEnv(ctx, cmd)
+//	err := cmd.Run()
+func PropagateToEnv(ctx context.Context, cmd *exec.Cmd) {
+	requestID := GetRequestIDFromContext(ctx)
+	correlationID := GetCorrelationIDFromContext(ctx)
+	if correlationID == "" {
+		correlationID = requestID
+	}
+
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	// later entries win in exec, so values inherited from the parent's own environment are overridden
+	cmd.Env = append(cmd.Env, EnvRequestID+"="+requestID, EnvCorrelationID+"="+correlationID)
+}
+
+// ContextFromEnv builds a background context from REQUEST_ID and CORRELATION_ID set by PropagateToEnv
+// Call it at startup of the child process. Values failing ValidateRequestID are ignored,
+// a missing request ID is generated and the correlation ID defaults to the request ID.
+//
+// Usage:
+//
+//	func main() {
+//		ctx := reqctx.ContextFromEnv()
+//		log.Info("helper started", "request_id", reqctx.GetRequestIDFromContext(ctx))
+//	}
+func ContextFromEnv() context.Context {
+	requestID := strings.TrimSpace(os.Getenv(EnvRequestID))
+	if ValidateRequestID(requestID) != nil {
+		requestID = NewRequestID()
+	}
+	correlationID := strings.TrimSpace(os.Getenv(EnvCorrelationID))
+	if ValidateRequestID(correlationID) != nil {
+		correlationID = requestID
+	}
+	return ContextWithTracing(context.Background(), TracingValues{RequestID: requestID, CorrelationID: correlationID})
+}
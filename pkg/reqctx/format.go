@@ -0,0 +1,371 @@
+package reqctx
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+const (
+	// HeaderB3 is the B3 single header, "{trace}-{span}[-{sampled}[-{parent}]]"
+	HeaderB3 = "b3"
+
+	// HeaderB3TraceID is the B3 multi header with the 16 or 32 hex digit trace ID
+	HeaderB3TraceID = "X-B3-TraceId"
+
+	// HeaderB3SpanID is the B3 multi header with the 16 hex digit span ID
+	HeaderB3SpanID = "X-B3-SpanId"
+
+	// HeaderB3ParentSpanID is the B3 multi header with the 16 hex digit parent span ID
+	HeaderB3ParentSpanID = "X-B3-ParentSpanId"
+
+	// HeaderB3Sampled is the B3 multi header with the sampling decision, "1" or "0"
+	HeaderB3Sampled = "X-B3-Sampled"
+
+	// HeaderB3Flags is the B3 multi header marking debug requests with "1", which implies sampled
+	HeaderB3Flags = "X-B3-Flags"
+
+	// HeaderDatadogTraceID is the Datadog header with the decimal 64-bit trace ID
+	HeaderDatadogTraceID = "X-Datadog-Trace-Id"
+
+	// HeaderDatadogParentID is the Datadog header with the decimal 64-bit span ID of the caller
+	HeaderDatadogParentID = "X-Datadog-Parent-Id"
+
+	// HeaderDatadogSamplingPriority is the Datadog header with the sampling priority, > 0 means sampled
+	HeaderDatadogSamplingPriority = "X-Datadog-Sampling-Priority"
+)
+
+// Format encodes the tracing headers of TracingHeadersFromContext into a wire format and back
+// Encode receives the native headers (X-Request-ID, X-Correlation-ID, ...) and writes their
+// representation in the format to carrier. Decode wraps a carrier holding the format, answering
+// Get for native header names, so the validation of ExtractContext applies to every format.
+type Format interface {
+	Encode(headers map[string]string, carrier Carrier)
+	Decode(carrier Extractor) Extractor
+}
+
+var (
+	// NativeFormat is the format of Inject and ExtractContext, headers are passed through as is
+	NativeFormat Format = nativeFormat{}
+
+	// B3MultiFormat maps the tracing IDs to the X-B3-* headers of Zipkin and Envoy
+	// The correlation ID becomes the trace ID, the request ID the span ID and the parent
+	// request ID the parent span ID. IDs that are not hex IDs of the required length
+	// (like UUIDs for span IDs) are hashed, so IDs received in B3 round-trip unchanged
+	// but native IDs can't be recovered from B3 headers.
+	B3MultiFormat Format = b3MultiFormat{}
+
+	// B3SingleFormat is B3MultiFormat in the single b3 header
+	B3SingleFormat Format = b3SingleFormat{}
+
+	// DatadogFormat maps the tracing IDs to the x-datadog-* headers as decimal 64-bit IDs
+	// The correlation ID becomes the trace ID and the request ID the parent ID, the span ID of
+	// the caller. Received IDs are stored as 16 hex digits, so they bridge to B3 unchanged.
+	DatadogFormat Format = datadogFormat{}
+)
+
+// InjectFormats writes the tracing identifiers of ctx to carrier in each of formats
+// All formats encode the same TracingHeadersFromContext, so a generated request ID is shared.
+// Without formats it is Inject. List NativeFormat too to send the native headers alongside.
+//
+// Usage:
+//
+//	// a Zipkin-instrumented service that doesn't know X-Request-ID
+//	reqctx.InjectFormats(ctx, reqctx.HeaderCarrier(req.Header), reqctx.NativeFormat, reqctx.B3MultiFormat)
+func InjectFormats(ctx context.Context, carrier Carrier, formats ...Format) {
+	if len(formats) == 0 {
+		formats = []Format{NativeFormat}
+	}
+	headers := TracingHeadersFromContext(ctx)
+	for _, format := range formats {
+		format.Encode(headers, carrier)
+	}
+}
+
+// ExtractFormats stores the tracing identifiers of carrier in ctx, reading each of formats
+// Every header is taken from the first format carrying it, so list the preferred format first.
+// As with ExtractContext, a request ID is generated if no format carries one. Without formats
+// it is ExtractContext. The carrier is untrusted, see ExtractFormatsWithOptions.
+//
+// Usage:
+//
+//	ctx := reqctx.ExtractFormats(ctx, reqctx.HeaderCarrier(r.Header),
+//		reqctx.NativeFormat, reqctx.B3SingleFormat, reqctx.B3MultiFormat, reqctx.DatadogFormat)
+func ExtractFormats(ctx context.Context, carrier Extractor, formats ...Format) context.Context {
+	return ExtractFormatsWithOptions(ctx, carrier, ExtractOptions{}, formats...)
+}
+
+// ExtractFormatsWithOptions is ExtractFormats honoring the carrier values opts allows, see ExtractOptions
+// B3 and Datadog sampling flags are read as X-Trace-Sampled, so they need opts.Trusted too.
+func ExtractFormatsWithOptions(ctx context.Context, carrier Extractor, opts ExtractOptions, formats ...Format) context.Context {
+	if len(formats) == 0 {
+		return ExtractContextWithOptions(ctx, carrier, opts)
+	}
+	decoders := make([]Extractor, len(formats))
+	for i, format := range formats {
+		decoders[i] = format.Decode(carrier)
+	}
+	return ExtractContextWithOptions(ctx, ExtractorFunc(func(key string) string {
+		for _, decoder := range decoders {
+			if value := decoder.Get(key); value != "" {
+				return value
+			}
+		}
+		return ""
+	}), opts)
+}
+
+// nativeFormat passes the native headers through
+type nativeFormat struct{}
+
+func (nativeFormat) Encode(headers map[string]string, carrier Carrier) {
+	for key, value := range headers {
+		carrier.Set(key, value)
+	}
+}
+
+func (nativeFormat) Decode(carrier Extractor) Extractor {
+	return carrier
+}
+
+// b3MultiFormat encodes to the X-B3-* headers
+type b3MultiFormat struct{}
+
+func (b3MultiFormat) Encode(headers map[string]string, carrier Carrier) {
+	ids := newB3IDs(headers)
+	carrier.Set(HeaderB3TraceID, ids.traceID)
+	carrier.Set(HeaderB3SpanID, ids.spanID)
+	if ids.parentSpanID != "" {
+		carrier.Set(HeaderB3ParentSpanID, ids.parentSpanID)
+	}
+	if ids.sampled != "" {
+		carrier.Set(HeaderB3Sampled, ids.sampled)
+	}
+}
+
+func (b3MultiFormat) Decode(carrier Extractor) Extractor {
+	return ExtractorFunc(func(key string) string {
+		switch key {
+		case HeaderCorrelationID:
+			return traceHex(carrier.Get(HeaderB3TraceID))
+		case HeaderRequestID:
+			return spanHex(carrier.Get(HeaderB3SpanID))
+		case HeaderParentRequestID:
+			return spanHex(carrier.Get(HeaderB3ParentSpanID))
+		case HeaderTraceSampled:
+			if strings.TrimSpace(carrier.Get(HeaderB3Flags)) == "1" {
+				return "1"
+			}
+			return b3SampledValue(carrier.Get(HeaderB3Sampled))
+		}
+		return ""
+	})
+}
+
+// b3SingleFormat encodes to the b3 header
+type b3SingleFormat struct{}
+
+func (b3SingleFormat) Encode(headers map[string]string, carrier Carrier) {
+	ids := newB3IDs(headers)
+	value := ids.traceID + "-" + ids.spanID
+	if ids.sampled != "" {
+		value += "-" + ids.sampled
+		if ids.parentSpanID != "" {
+			value += "-" + ids.parentSpanID
+		}
+	}
+	carrier.Set(HeaderB3, value)
+}
+
+func (b3SingleFormat) Decode(carrier Extractor) Extractor {
+	return ExtractorFunc(func(key string) string {
+		// "{trace}-{span}[-{sampled}[-{parent}]]", or a lone sampling decision
+		parts := strings.Split(strings.TrimSpace(carrier.Get(HeaderB3)), "-")
+		if len(parts) == 1 {
+			if key == HeaderTraceSampled {
+				return b3SampledValue(parts[0])
+			}
+			return ""
+		}
+		switch key {
+		case HeaderCorrelationID:
+			return traceHex(parts[0])
+		case HeaderRequestID:
+			return spanHex(parts[1])
+		case HeaderTraceSampled:
+			if len(parts) > 2 {
+				return b3SampledValue(parts[2])
+			}
+		case HeaderParentRequestID:
+			if len(parts) > 3 {
+				return spanHex(parts[3])
+			}
+		}
+		return ""
+	})
+}
+
+// b3IDs are the native headers converted to B3 IDs
+type b3IDs struct {
+	traceID      string
+	spanID       string
+	parentSpanID string
+	sampled      string
+}
+
+// newB3IDs converts the native headers, the trace ID falls back to the request ID
+func newB3IDs(headers map[string]string) b3IDs {
+	traceSource := headers[HeaderCorrelationID]
+	if traceSource == "" {
+		traceSource = headers[HeaderRequestID]
+	}
+	ids := b3IDs{
+		traceID: traceIDHex(traceSource),
+		spanID:  toHexID(headers[HeaderRequestID], 16),
+		sampled: headers[HeaderTraceSampled],
+	}
+	if parentID := headers[HeaderParentRequestID]; parentID != "" {
+		ids.parentSpanID = toHexID(parentID, 16)
+	}
+	return ids
+}
+
+// b3SampledValue converts a B3 sampling decision to an X-Trace-Sampled value, "d" (debug) is sampled
+func b3SampledValue(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "d", "true":
+		return "1"
+	case "0", "false":
+		return "0"
+	}
+	return ""
+}
+
+// datadogFormat encodes to the x-datadog-* headers
+type datadogFormat struct{}
+
+func (datadogFormat) Encode(headers map[string]string, carrier Carrier) {
+	traceSource := headers[HeaderCorrelationID]
+	if traceSource == "" {
+		traceSource = headers[HeaderRequestID]
+	}
+	// the low 64 bits of the B3 trace ID, so both formats name the same trace
+	carrier.Set(HeaderDatadogTraceID, datadogID(toHexID(traceIDHex(traceSource), 16)))
+	carrier.Set(HeaderDatadogParentID, datadogID(toHexID(headers[HeaderRequestID], 16)))
+	switch headers[HeaderTraceSampled] {
+	case "1":
+		carrier.Set(HeaderDatadogSamplingPriority, "1")
+	case "0":
+		carrier.Set(HeaderDatadogSamplingPriority, "0")
+	}
+}
+
+func (datadogFormat) Decode(carrier Extractor) Extractor {
+	return ExtractorFunc(func(key string) string {
+		switch key {
+		case HeaderCorrelationID:
+			return datadogHex(carrier.Get(HeaderDatadogTraceID))
+		case HeaderRequestID:
+			return datadogHex(carrier.Get(HeaderDatadogParentID))
+		case HeaderTraceSampled:
+			priority, err := strconv.Atoi(strings.TrimSpace(carrier.Get(HeaderDatadogSamplingPriority)))
+			switch {
+			case err != nil:
+				return ""
+			case priority > 0:
+				return "1"
+			}
+			return "0"
+		}
+		return ""
+	})
+}
+
+// datadogID formats a 16 hex digit ID as the decimal Datadog ID
+func datadogID(hexID string) string {
+	id, _ := strconv.ParseUint(hexID, 16, 64)
+	return strconv.FormatUint(id, 10)
+}
+
+// datadogHex parses a decimal Datadog ID to 16 hex digits, empty if invalid or zero
+func datadogHex(value string) string {
+	id, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+	if err != nil || id == 0 {
+		return ""
+	}
+	return hex.EncodeToString([]byte{
+		byte(id >> 56), byte(id >> 48), byte(id >> 40), byte(id >> 32),
+		byte(id >> 24), byte(id >> 16), byte(id >> 8), byte(id),
+	})
+}
+
+// traceIDHex converts id to a B3 trace ID, keeping IDs that already are 16 or 32 hex digits
+func traceIDHex(id string) string {
+	if normalized := normalizeHexID(id); isHexID(normalized, 16) {
+		return normalized
+	}
+	return toHexID(id, 32)
+}
+
+// toHexID converts id to n lowercase hex digits
+// Hex IDs of n digits are kept, with dashes removed so UUIDs qualify. 16 digit IDs are padded
+// to 32 and 32 digit IDs are cut to their low 64 bits for 16, like tracers bridging 64 and
+// 128-bit IDs do. Anything else is hashed.
+func toHexID(id string, n int) string {
+	normalized := normalizeHexID(id)
+	switch {
+	case isHexID(normalized, n):
+		return normalized
+	case n == 32 && isHexID(normalized, 16):
+		return strings.Repeat("0", 16) + normalized
+	case n == 16 && isHexID(normalized, 32):
+		if low := normalized[16:]; !isZeroHex(low) {
+			return low
+		}
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:n/2])
+}
+
+// traceHex returns value if it is a valid B3 trace ID, otherwise empty string
+func traceHex(value string) string {
+	value = strings.TrimSpace(value)
+	if isHexID(value, 16) || isHexID(value, 32) {
+		return value
+	}
+	return ""
+}
+
+// spanHex returns value if it is a valid B3 span ID, otherwise empty string
+func spanHex(value string) string {
+	value = strings.TrimSpace(value)
+	if isHexID(value, 16) {
+		return value
+	}
+	return ""
+}
+
+// normalizeHexID lowercases id and removes dashes
+func normalizeHexID(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "-", ""))
+}
+
+// isHexID reports whether s is n lowercase hex digits and not all zeros, which B3 forbids
+func isHexID(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'a' || s[i] > 'f') {
+			return false
+		}
+	}
+	return !isZeroHex(s)
+}
+
+// isZeroHex reports whether s consists of zeros only
+func isZeroHex(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
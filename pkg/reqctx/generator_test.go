@@ -0,0 +1,64 @@
+package reqctx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestUUIDGeneratorRNGFailure(t *testing.T) {
+	tests := []struct {
+		name         string
+		newUUID      func() (uuid.UUID, error)
+		wantFallback bool
+	}{
+		{name: "working RNG", newUUID: uuid.NewRandom},
+		{
+			name:         "failing RNG",
+			newUUID:      func() (uuid.UUID, error) { return uuid.Nil, errors.New("entropy exhausted") },
+			wantFallback: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := newRandomUUID
+			newRandomUUID = tt.newUUID
+			t.Cleanup(func() { newRandomUUID = orig })
+
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("Generate panicked: %v", r)
+				}
+			}()
+
+			first, second := UUIDGenerator{}.Generate(), UUIDGenerator{}.Generate()
+			if first == second {
+				t.Errorf("two IDs are equal: %q", first)
+			}
+			for _, id := range []string{first, second} {
+				if err := ValidateRequestID(id); err != nil {
+					t.Errorf("ID %q is invalid: %v", id, err)
+				}
+				_, parseErr := uuid.Parse(id)
+				if isFallback := strings.HasPrefix(id, "t") && parseErr != nil; isFallback != tt.wantFallback {
+					t.Errorf("ID %q fallback = %v, want %v", id, isFallback, tt.wantFallback)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRequestIDRNGFailure(t *testing.T) {
+	orig := newRandomUUID
+	newRandomUUID = func() (uuid.UUID, error) { return uuid.Nil, errors.New("entropy exhausted") }
+	t.Cleanup(func() { newRandomUUID = orig })
+
+	SetServicePrefix("payments")
+	t.Cleanup(func() { SetServicePrefix("") })
+
+	if id := NewRequestID(); !strings.HasPrefix(id, "payments-t") {
+		t.Errorf("NewRequestID = %q, want a prefixed fallback ID", id)
+	}
+}
@@ -0,0 +1,46 @@
+package reqctx
+
+import "context"
+
+// HeaderParentRequestID carries the request ID of the caller that spawned the request
+const HeaderParentRequestID = "X-Parent-Request-ID"
+
+// NewChildRequestID generates a request ID for an outbound call and records the current one as its parent
+// The returned context carries the child as request_id, the current request ID as parent and an
+// unchanged correlation_id (the current request ID becomes the correlation ID if none is set),
+// so the whole tree shares one correlation ID. Propagation sends the parent in X-Parent-Request-ID.
+// The child is recorded for ChildRequestIDsFromContext if ctx has a collector.
+//
+// Usage:
+//
+//	childID, callCtx := reqctx.NewChildRequestID(ctx)
+//	req, _ := http.NewRequestWithContext(callCtx, "GET", url, nil)
+//	log.Info("calling inventory", "child_request_id", childID)
+func NewChildRequestID(ctx context.Context) (string, context.Context) {
+	parentID := GetRequestIDFromContext(ctx)
+	correlationID := GetCorrelationIDFromContext(ctx)
+	if correlationID == "" {
+		correlationID = parentID
+	}
+
+	childID := NewRequestID()
+	RecordChildRequestID(ctx, childID)
+	return childID, ContextWithTracing(ctx, TracingValues{
+		RequestID:       childID,
+		CorrelationID:   correlationID,
+		ParentRequestID: parentID,
+	})
+}
+
+// ContextWithParentRequestID creates a new context with parent_request_id value
+func ContextWithParentRequestID(ctx context.Context, parentID string) context.Context {
+	values := tracingValue.Value(ctx)
+	values.ParentRequestID = parentID
+	return tracingValue.With(ctx, values)
+}
+
+// ParentRequestIDFromContext returns the parent request ID and whether it was found
+func ParentRequestIDFromContext(ctx context.Context) (string, bool) {
+	parentID := tracingValue.Value(ctx).ParentRequestID
+	return parentID, parentID != ""
+}
@@ -0,0 +1,45 @@
+package reqctx
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// HeaderRequestHops carries the number of services a request has passed, incremented on every propagation
+const HeaderRequestHops = "X-Request-Hops"
+
+// hopsValue holds the hop count of the current service
+var hopsValue = NewContextValue[int]("request_hops")
+
+// ContextWithHopCount creates a new context with the hop count of the current service
+func ContextWithHopCount(ctx context.Context, hops int) context.Context {
+	return hopsValue.With(ctx, hops)
+}
+
+// HopCountFromContext returns the number of services the request passed before this one, 0 at the origin
+// Every propagation sends the current count plus one in X-Request-Hops. Unlike the trace sequence,
+// which orders log records, the hop count is meant for loop detection, see httputil.MaxHops.
+//
+// Usage:
+//
+//	if reqctx.HopCountFromContext(ctx) > 10 {
+//		log.Warn("deep call chain", "request_id", reqctx.GetRequestIDFromContext(ctx))
+//	}
+func HopCountFromContext(ctx context.Context) int {
+	return hopsValue.Value(ctx)
+}
+
+// ParseHopCount parses an X-Request-Hops value, ok is false for anything but a non-negative integer
+func ParseHopCount(value string) (hops int, ok bool) {
+	hops, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || hops < 0 {
+		return 0, false
+	}
+	return hops, true
+}
+
+// hopsHeaderValue returns the X-Request-Hops value for calls made from ctx
+func hopsHeaderValue(ctx context.Context) string {
+	return strconv.Itoa(HopCountFromContext(ctx) + 1)
+}
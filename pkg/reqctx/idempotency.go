@@ -0,0 +1,43 @@
+package reqctx
+
+import (
+	"context"
+	"errors"
+)
+
+// HeaderIdempotencyKey carries the client-chosen key used to deduplicate retried requests
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// MaxIdempotencyKeyLength is the maximum accepted length of an idempotency key
+const MaxIdempotencyKeyLength = 255
+
+// idempotencyKeyKey is the context key for idempotency key
+const idempotencyKeyKey contextKey = "idempotency_key"
+
+// ErrInvalidIdempotencyKey is returned by ValidateIdempotencyKey for malformed keys
+var ErrInvalidIdempotencyKey = errors.New("reqctx: invalid idempotency key")
+
+// ValidateIdempotencyKey checks that key is 1 to MaxIdempotencyKeyLength visible ASCII characters
+// UUIDs and opaque tokens are both accepted
+func ValidateIdempotencyKey(key string) error {
+	if key == "" || len(key) > MaxIdempotencyKeyLength {
+		return ErrInvalidIdempotencyKey
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return ErrInvalidIdempotencyKey
+		}
+	}
+	return nil
+}
+
+// ContextWithIdempotencyKey creates a new context with idempotency key value
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key and whether it was found
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, _ := ctx.Value(idempotencyKeyKey).(string)
+	return key, key != ""
+}
@@ -0,0 +1,43 @@
+package reqctx
+
+import (
+	"context"
+	"strings"
+)
+
+// NewJobContext returns a background context with a synthetic request ID for a cron or scheduled job
+// The ID is the job name prefix plus a generated ID, e.g. "cron-cleanup-<uuid>", after the
+// SetServicePrefix prefix if set: "payments-cron-cleanup-<uuid>". It is also the correlation ID,
+// so downstream calls of the run propagate it like for an HTTP request. Characters of jobName
+// that are not valid in request IDs become '-'.
+//
+// Usage:
+//
+//	c.AddFunc("@hourly", func() {
+//		ctx := reqctx.NewJobContext("cron-cleanup")
+//		log.Info("cleanup started", "request_id", reqctx.GetRequestIDFromContext(ctx))
+//		cleanup(ctx)
+//	})
+func NewJobContext(jobName string) context.Context {
+	prefix := jobIDPrefix(jobName)
+	if service := GetServicePrefix(); service != "" {
+		if prefix != "" {
+			prefix = service + "-" + prefix
+		} else {
+			prefix = service
+		}
+	}
+
+	requestID := NewPrefixedID(GetIDGenerator(), prefix)
+	return ContextWithTracing(context.Background(), TracingValues{RequestID: requestID, CorrelationID: requestID})
+}
+
+// jobIDPrefix replaces characters not allowed by ValidateRequestID with '-'
+func jobIDPrefix(jobName string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x21 || r > 0x7e {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(jobName))
+}
@@ -0,0 +1,44 @@
+package reqctx
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// HeaderRequestLocale carries the resolved locale of the original client as a BCP 47 tag
+const HeaderRequestLocale = "X-Request-Locale"
+
+// localeValue holds the request locale
+var localeValue = NewContextValue[language.Tag]("request_locale")
+
+// ContextWithLocale creates a new context with the request locale
+func ContextWithLocale(ctx context.Context, tag language.Tag) context.Context {
+	return localeValue.With(ctx, tag)
+}
+
+// LocaleFromContext returns the request locale and whether it was found
+//
+// Usage:
+//
+//	if tag, ok := reqctx.LocaleFromContext(ctx); ok {
+//		printer := message.NewPrinter(tag)
+//		// ...
+//	}
+func LocaleFromContext(ctx context.Context) (language.Tag, bool) {
+	return localeValue.Get(ctx)
+}
+
+// ParseLocale parses an X-Request-Locale value, ok is false for malformed and undefined tags
+func ParseLocale(value string) (tag language.Tag, ok bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return language.Und, false
+	}
+	tag, err := language.Parse(value)
+	if err != nil || tag == language.Und {
+		return language.Und, false
+	}
+	return tag, true
+}
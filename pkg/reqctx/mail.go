@@ -0,0 +1,51 @@
+package reqctx
+
+import (
+	"context"
+	"net/mail"
+	"net/textproto"
+)
+
+// InjectMail stamps X-Request-ID and X-Correlation-ID from ctx on an outgoing email through carrier
+// Bounce and complaint reports usually quote the original headers, so they can be traced back to
+// the request that sent the mail. Only the two IDs are written: baggage, tenant and the other
+// tracing headers stay internal. Like outgoing HTTP headers, the correlation ID defaults to the
+// request ID and a request ID is generated if ctx has none. Control characters are removed,
+// so a crafted ID can't inject further headers.
+//
+// Any mail library works through reqctx.CarrierFunc and its header setter.
+//
+// Usage:
+//
+//	msg := gomail.NewMessage()
+//	reqctx.InjectMail(ctx, reqctx.CarrierFunc(func(key, value string) {
+//		msg.SetHeader(key, value)
+//	}))
+//
+//	// net/smtp: write the header map before the body
+//	h := mail.Header{"Subject": {"Your order"}}
+//	reqctx.InjectMail(ctx, reqctx.MailHeaderCarrier(h))
+func InjectMail(ctx context.Context, carrier Carrier) {
+	requestID := GetRequestIDFromContext(ctx)
+	correlationID := GetCorrelationIDFromContext(ctx)
+	if correlationID == "" {
+		correlationID = requestID
+	}
+
+	carrier.Set(HeaderRequestID, SanitizeHeaderValue(requestID))
+	carrier.Set(HeaderCorrelationID, SanitizeHeaderValue(correlationID))
+}
+
+// MailHeaderCarrier adapts mail.Header to Carrier and Extractor, keys are canonicalized like textproto does
+// Extract reads the IDs back from a parsed bounce, e.g. the headers of its message/rfc822 part.
+type MailHeaderCarrier mail.Header
+
+// Set implements Carrier
+func (h MailHeaderCarrier) Set(key, value string) {
+	h[textproto.CanonicalMIMEHeaderKey(key)] = []string{value}
+}
+
+// Get implements Extractor
+func (h MailHeaderCarrier) Get(key string) string {
+	return mail.Header(h).Get(key)
+}
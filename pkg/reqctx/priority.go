@@ -0,0 +1,76 @@
+package reqctx
+
+import (
+	"context"
+	"strings"
+)
+
+// HeaderRequestPriority carries the request priority class for downstream load shedding
+const HeaderRequestPriority = "X-Request-Priority"
+
+// Priority is the QoS class of a request, higher values are more important
+// The zero value is PriorityNormal, so priorities compare with < and >.
+type Priority int
+
+const (
+	// PriorityLow marks batch and background traffic, shed first under load
+	PriorityLow Priority = -1
+
+	// PriorityNormal is the default for requests without a priority
+	PriorityNormal Priority = 0
+
+	// PriorityHigh marks interactive user traffic, shed last
+	PriorityHigh Priority = 1
+)
+
+// String returns the X-Request-Priority value of p: "low", "normal" or "high"
+func (p Priority) String() string {
+	switch {
+	case p < PriorityNormal:
+		return "low"
+	case p > PriorityNormal:
+		return "high"
+	}
+	return "normal"
+}
+
+// ParsePriority parses an X-Request-Priority value case-insensitively, ok is false for unknown values
+func ParsePriority(value string) (p Priority, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "low":
+		return PriorityLow, true
+	case "normal":
+		return PriorityNormal, true
+	case "high":
+		return PriorityHigh, true
+	}
+	return PriorityNormal, false
+}
+
+// priorityValue holds the request priority
+var priorityValue = NewContextValue[Priority]("request_priority")
+
+// ContextWithPriority creates a new context with the request priority
+func ContextWithPriority(ctx context.Context, p Priority) context.Context {
+	return priorityValue.With(ctx, p)
+}
+
+// PriorityFromContext returns the request priority, PriorityNormal if none is set
+//
+// Usage:
+//
+//	if reqctx.PriorityFromContext(ctx) == reqctx.PriorityLow && shedder.Overloaded() {
+//		return ErrShed
+//	}
+func PriorityFromContext(ctx context.Context) Priority {
+	return priorityValue.Value(ctx)
+}
+
+// priorityHeaderValue returns the X-Request-Priority value for ctx, empty if ctx has no priority
+func priorityHeaderValue(ctx context.Context) string {
+	p, ok := priorityValue.Get(ctx)
+	if !ok {
+		return ""
+	}
+	return p.String()
+}
@@ -0,0 +1,136 @@
+package reqctx
+
+import "context"
+
+// ContextKey names a request-scoped value ProjectContext can copy
+type ContextKey int
+
+const (
+	// ContextKeyRequestID is the request ID of ContextWithRequestID
+	ContextKeyRequestID ContextKey = iota + 1
+
+	// ContextKeyCorrelationID is the correlation ID of ContextWithCorrelationID
+	ContextKeyCorrelationID
+
+	// ContextKeyParentRequestID is the parent request ID of ContextWithParentRequestID
+	ContextKeyParentRequestID
+
+	// ContextKeyIdempotencyKey is the key of ContextWithIdempotencyKey
+	ContextKeyIdempotencyKey
+
+	// ContextKeyTenantID is the tenant of ContextWithTenantID
+	ContextKeyTenantID
+
+	// ContextKeyBaggage is the baggage of ContextWithBaggage
+	ContextKeyBaggage
+
+	// ContextKeySampled is the sampling decision of ContextWithSampled
+	ContextKeySampled
+
+	// ContextKeyPriority is the priority of ContextWithPriority
+	ContextKeyPriority
+
+	// ContextKeyLocale is the locale of ContextWithLocale
+	ContextKeyLocale
+
+	// ContextKeyClientIP is the client IP of ContextWithClientIP
+	ContextKeyClientIP
+
+	// ContextKeyRiskScore is the score of ContextWithRiskScore
+	ContextKeyRiskScore
+
+	// ContextKeyAPIVersion is the version of ContextWithAPIVersion
+	ContextKeyAPIVersion
+
+	// ContextKeyDryRun is the flag of ContextWithDryRun
+	ContextKeyDryRun
+
+	// ContextKeySequence is the hop number of ContextWithSequence
+	ContextKeySequence
+
+	// ContextKeyHopCount is the hop count of ContextWithHopCount
+	ContextKeyHopCount
+)
+
+// ProjectContext returns a new background context holding only the listed values of ctx
+// Use it at service boundaries to hand on request IDs without leaking anything else stored in ctx
+// (user data, cancellation, deadlines). Values missing in ctx and unknown keys are skipped.
+//
+// Usage:
+//
+//	ctx = reqctx.ProjectContext(ctx, reqctx.ContextKeyRequestID, reqctx.ContextKeyCorrelationID)
+//	partner.Notify(ctx, event)
+func ProjectContext(ctx context.Context, keys ...ContextKey) context.Context {
+	dst := context.Background()
+	for _, key := range keys {
+		dst = copyContextValue(dst, ctx, key)
+	}
+	return dst
+}
+
+// copyContextValue copies the value of key from src onto dst if src has it
+func copyContextValue(dst, src context.Context, key ContextKey) context.Context {
+	switch key {
+	case ContextKeyRequestID:
+		if requestID, ok := RequestIDFromContext(src); ok {
+			return ContextWithRequestID(dst, requestID)
+		}
+	case ContextKeyCorrelationID:
+		if correlationID := GetCorrelationIDFromContext(src); correlationID != "" {
+			return ContextWithCorrelationID(dst, correlationID)
+		}
+	case ContextKeyParentRequestID:
+		if parentID, ok := ParentRequestIDFromContext(src); ok {
+			return ContextWithParentRequestID(dst, parentID)
+		}
+	case ContextKeyIdempotencyKey:
+		if idempotencyKey, ok := IdempotencyKeyFromContext(src); ok {
+			return ContextWithIdempotencyKey(dst, idempotencyKey)
+		}
+	case ContextKeyTenantID:
+		if tenantID, ok := TenantIDFromContext(src); ok {
+			return ContextWithTenantID(dst, tenantID)
+		}
+	case ContextKeyBaggage:
+		if members := baggageFromContext(src); len(members) > 0 {
+			return contextWithBaggageMembers(dst, members)
+		}
+	case ContextKeySampled:
+		if sampled, ok := sampledValue.Get(src); ok {
+			return ContextWithSampled(dst, sampled)
+		}
+	case ContextKeyPriority:
+		if priority, ok := priorityValue.Get(src); ok {
+			return ContextWithPriority(dst, priority)
+		}
+	case ContextKeyLocale:
+		if tag, ok := LocaleFromContext(src); ok {
+			return ContextWithLocale(dst, tag)
+		}
+	case ContextKeyClientIP:
+		if ip, ok := ClientIPFromContext(src); ok {
+			return ContextWithClientIP(dst, ip)
+		}
+	case ContextKeyRiskScore:
+		if score, ok := riskScoreValue.Get(src); ok {
+			return ContextWithRiskScore(dst, score)
+		}
+	case ContextKeyAPIVersion:
+		if version, ok := APIVersionFromContext(src); ok {
+			return ContextWithAPIVersion(dst, version)
+		}
+	case ContextKeyDryRun:
+		if dryRun, ok := dryRunValue.Get(src); ok {
+			return ContextWithDryRun(dst, dryRun)
+		}
+	case ContextKeySequence:
+		if seq, ok := sequenceValue.Get(src); ok {
+			return ContextWithSequence(dst, seq)
+		}
+	case ContextKeyHopCount:
+		if hops, ok := hopsValue.Get(src); ok {
+			return ContextWithHopCount(dst, hops)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,92 @@
+package reqctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// HeaderRiskScore carries the fraud / abuse score computed at the edge, a number in [0, 1]
+const HeaderRiskScore = "X-Risk-Score"
+
+// ErrRiskScoreOutOfRange is returned by ParseRiskScore for scores outside [0, 1], the score is clamped
+var ErrRiskScoreOutOfRange = errors.New("reqctx: risk score out of range [0, 1]")
+
+// riskScoreValue holds the risk score
+var riskScoreValue = NewContextValue[float64]("risk_score")
+
+// ContextWithRiskScore creates a new context with the risk score, clamped to [0, 1]
+func ContextWithRiskScore(ctx context.Context, score float64) context.Context {
+	return riskScoreValue.With(ctx, clampRiskScore(score))
+}
+
+// RiskScoreFromContext returns the risk score and whether it was found
+//
+// Usage:
+//
+//	if score, ok := reqctx.RiskScoreFromContext(ctx); ok && score > 0.8 {
+//		return ErrStepUpRequired
+//	}
+func RiskScoreFromContext(ctx context.Context) (float64, bool) {
+	return riskScoreValue.Get(ctx)
+}
+
+// ParseRiskScore parses an X-Risk-Score value
+// Scores outside [0, 1] are clamped and returned with ErrRiskScoreOutOfRange, malformed values,
+// NaN and infinities return another error.
+func ParseRiskScore(value string) (float64, error) {
+	score, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(score) || math.IsInf(score, 0) {
+		return 0, fmt.Errorf("reqctx: invalid risk score %q", value)
+	}
+	if clamped := clampRiskScore(score); clamped != score {
+		return clamped, fmt.Errorf("%w: %v", ErrRiskScoreOutOfRange, score)
+	}
+	return score, nil
+}
+
+// ContextWithRiskScoreHeader stores an X-Risk-Score value in ctx
+// Out-of-range scores are clamped with a warning log, malformed values are dropped. ctx is
+// returned unchanged for an empty value.
+func ContextWithRiskScoreHeader(ctx context.Context, value string) context.Context {
+	if value == "" {
+		return ctx
+	}
+	score, err := ParseRiskScore(value)
+	switch {
+	case errors.Is(err, ErrRiskScoreOutOfRange):
+		requestID, _ := RequestIDFromContext(ctx)
+		slog.WarnContext(ctx, "reqctx: risk score clamped",
+			slog.String("value", value),
+			slog.Float64("score", score),
+			slog.String(LogKeyRequestID, requestID),
+		)
+	case err != nil:
+		return ctx
+	}
+	return ContextWithRiskScore(ctx, score)
+}
+
+// riskScoreHeaderValue returns the X-Risk-Score value for ctx, empty if ctx has no score
+func riskScoreHeaderValue(ctx context.Context) string {
+	score, ok := riskScoreValue.Get(ctx)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+// clampRiskScore limits score to [0, 1], NaN becomes 0
+func clampRiskScore(score float64) float64 {
+	switch {
+	case !(score > 0):
+		return 0
+	case score > 1:
+		return 1
+	}
+	return score
+}
@@ -0,0 +1,50 @@
+package reqctx
+
+import "context"
+
+// HeaderTraceSampled carries the upstream sampling decision, "1" for sampled and "0" for not sampled
+const HeaderTraceSampled = "X-Trace-Sampled"
+
+// sampledValue holds the sampling decision
+var sampledValue = NewContextValue[bool]("trace_sampled")
+
+// ContextWithSampled creates a new context with the sampling decision
+func ContextWithSampled(ctx context.Context, sampled bool) context.Context {
+	return sampledValue.With(ctx, sampled)
+}
+
+// IsSampled reports whether the request is sampled for verbose logs and traces
+// Requests without a decision are sampled, so traces are never lost silently.
+//
+// Usage:
+//
+//	if reqctx.IsSampled(ctx) {
+//		log.Debug("cache lookup", "key", key, "request_id", reqctx.GetRequestIDFromContext(ctx))
+//	}
+func IsSampled(ctx context.Context) bool {
+	sampled, ok := sampledValue.Get(ctx)
+	return sampled || !ok
+}
+
+// ParseSampled parses an X-Trace-Sampled value, ok is false for anything but "0" and "1"
+func ParseSampled(value string) (sampled, ok bool) {
+	switch value {
+	case "1":
+		return true, true
+	case "0":
+		return false, true
+	}
+	return false, false
+}
+
+// sampledHeaderValue returns the X-Trace-Sampled value for ctx, empty if ctx has no decision
+func sampledHeaderValue(ctx context.Context) string {
+	sampled, ok := sampledValue.Get(ctx)
+	switch {
+	case !ok:
+		return ""
+	case sampled:
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,23 @@
+package reqctx
+
+import (
+	"context"
+	"hash/fnv"
+)
+
+// RequestIDShard returns a stable shard index in [0, numShards) derived from the request ID of ctx
+// The index is the 32-bit FNV-1a hash of the ID modulo numShards, so every service computes the same
+// shard for the same ID. A missing request ID or numShards <= 0 yields 0, no ID is generated.
+//
+// Usage:
+//
+//	buffers[reqctx.RequestIDShard(ctx, len(buffers))].Append(line)
+func RequestIDShard(ctx context.Context, numShards int) int {
+	requestID, ok := RequestIDFromContext(ctx)
+	if !ok || numShards <= 0 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(requestID))
+	return int(h.Sum32() % uint32(numShards))
+}
@@ -0,0 +1,64 @@
+package reqctx
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// attemptValue holds the attempt number of contexts from Snapshot.NewAttempt
+var attemptValue = NewContextValue[int]("attempt")
+
+// Snapshot freezes the tracing identity of a context for retry loops
+// Every NewAttempt context gets a fresh request ID while correlation_id, tenant_id, baggage and
+// the other request-scoped values stay those of the snapshotted context. Create it with
+// SnapshotContext. A Snapshot is safe for concurrent use, copies share the attempt counter.
+type Snapshot struct {
+	ctx      context.Context
+	parentID string
+	attempts *atomic.Int64
+}
+
+// SnapshotContext captures ctx for NewAttempt
+// The request ID of ctx becomes the parent of every attempt and, if ctx has no correlation ID,
+// the correlation ID shared by all attempts. A request ID is generated if ctx has none.
+//
+// Usage:
+//
+//	snap := reqctx.SnapshotContext(ctx)
+//	for {
+//		attemptCtx := snap.NewAttempt()
+//		log.Info("charging card", "request_id", reqctx.GetRequestIDFromContext(attemptCtx),
+//			"attempt", reqctx.AttemptFromContext(attemptCtx))
+//		if err := charge(attemptCtx); err == nil || snap.Attempts() == 3 {
+//			break
+//		}
+//	}
+func SnapshotContext(ctx context.Context) Snapshot {
+	parentID := GetRequestIDFromContext(ctx)
+	if GetCorrelationIDFromContext(ctx) == "" {
+		ctx = ContextWithCorrelationID(ctx, parentID)
+	}
+	return Snapshot{ctx: ctx, parentID: parentID, attempts: new(atomic.Int64)}
+}
+
+// NewAttempt returns a context for the next attempt with a new request ID and the attempt number
+// Attempts are numbered from 1, see AttemptFromContext. The context keeps the cancellation and
+// deadline of the snapshotted context.
+func (s Snapshot) NewAttempt() context.Context {
+	attempt := int(s.attempts.Add(1))
+	requestID := NewRequestID()
+	RecordChildRequestID(s.ctx, requestID)
+
+	ctx := ContextWithParentRequestID(ContextWithRequestID(s.ctx, requestID), s.parentID)
+	return attemptValue.With(ctx, attempt)
+}
+
+// Attempts returns the number of NewAttempt calls so far
+func (s Snapshot) Attempts() int {
+	return int(s.attempts.Load())
+}
+
+// AttemptFromContext returns the attempt number of a NewAttempt context, 0 for other contexts
+func AttemptFromContext(ctx context.Context) int {
+	return attemptValue.Value(ctx)
+}
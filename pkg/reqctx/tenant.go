@@ -0,0 +1,79 @@
+package reqctx
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// HeaderTenantID carries the tenant the request acts for
+const HeaderTenantID = "X-Tenant-ID"
+
+// MaxTenantIDLength is the maximum length of a tenant ID accepted by ValidateTenantID
+const MaxTenantIDLength = 64
+
+// ErrInvalidTenantID is returned by ValidateTenantID for malformed tenant IDs
+var ErrInvalidTenantID = errors.New("reqctx: invalid tenant id")
+
+// tenantIDValidatorHolder keeps a single concrete type inside atomic.Value
+type tenantIDValidatorHolder struct {
+	validate func(tenantID string) error
+}
+
+var tenantIDValidator atomic.Value
+
+func init() {
+	tenantIDValidator.Store(tenantIDValidatorHolder{validate: defaultValidateTenantID})
+}
+
+// SetTenantIDValidator replaces the tenant ID format check used by ValidateTenantID
+// Passing nil restores the default: 1 to MaxTenantIDLength ASCII letters, digits, '-' and '_'.
+// Safe for concurrent use.
+//
+// Usage:
+//
+//	reqctx.SetTenantIDValidator(func(id string) error {
+//		if _, err := uuid.Parse(id); err != nil {
+//			return reqctx.ErrInvalidTenantID
+//		}
+//		return nil
+//	})
+func SetTenantIDValidator(validate func(tenantID string) error) {
+	if validate == nil {
+		validate = defaultValidateTenantID
+	}
+	tenantIDValidator.Store(tenantIDValidatorHolder{validate: validate})
+}
+
+// ValidateTenantID checks tenantID with the validator set by SetTenantIDValidator
+func ValidateTenantID(tenantID string) error {
+	return tenantIDValidator.Load().(tenantIDValidatorHolder).validate(tenantID)
+}
+
+// defaultValidateTenantID accepts 1 to MaxTenantIDLength ASCII letters, digits, '-' and '_'
+func defaultValidateTenantID(tenantID string) error {
+	if tenantID == "" || len(tenantID) > MaxTenantIDLength {
+		return ErrInvalidTenantID
+	}
+	for i := 0; i < len(tenantID); i++ {
+		ch := tenantID[i]
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '-' || ch == '_') {
+			return ErrInvalidTenantID
+		}
+	}
+	return nil
+}
+
+// ContextWithTenantID creates a new context with tenant_id value
+// The tenant ID is sent downstream in X-Tenant-ID like the request ID.
+func ContextWithTenantID(ctx context.Context, tenantID string) context.Context {
+	values := tracingValue.Value(ctx)
+	values.TenantID = tenantID
+	return tracingValue.With(ctx, values)
+}
+
+// TenantIDFromContext returns the tenant_id and whether it was found
+func TenantIDFromContext(ctx context.Context) (string, bool) {
+	tenantID := tracingValue.Value(ctx).TenantID
+	return tenantID, tenantID != ""
+}
@@ -0,0 +1,94 @@
+package reqctx
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ExtractRequestIDFromText returns the first request ID logged in line
+// Both forms the log helpers produce are recognized: logfmt request_id=<id> (quoted or not) and
+// JSON "request_id":"<id>". Keys that merely end in request_id, such as parent_request_id, are
+// skipped. Any ID passing ValidateRequestID is returned: UUIDs, short IDs and prefixed IDs alike.
+//
+// Usage:
+//
+//	scanner := bufio.NewScanner(os.Stdin)
+//	for scanner.Scan() {
+//		if id, ok := reqctx.ExtractRequestIDFromText(scanner.Text()); ok {
+//			counts[id]++
+//		}
+//	}
+func ExtractRequestIDFromText(line string) (string, bool) {
+	for offset := 0; ; {
+		i := strings.Index(line[offset:], LogKeyRequestID)
+		if i < 0 {
+			return "", false
+		}
+		start := offset + i
+		offset = start + len(LogKeyRequestID)
+
+		if id, ok := requestIDAt(line, start); ok {
+			return id, true
+		}
+	}
+}
+
+// requestIDAt parses the value of a request_id key found at line[start:]
+func requestIDAt(line string, start int) (string, bool) {
+	end := start + len(LogKeyRequestID)
+	quoted := start > 0 && line[start-1] == '"'
+	if quoted {
+		start--
+		if end >= len(line) || line[end] != '"' {
+			return "", false
+		}
+		end++
+	}
+	if start > 0 && isKeyChar(line[start-1]) {
+		return "", false
+	}
+
+	rest := line[end:]
+	if quoted {
+		// JSON: "request_id": "<id>"
+		rest = strings.TrimLeft(rest, " \t")
+		if !strings.HasPrefix(rest, ":") {
+			return "", false
+		}
+		rest = strings.TrimLeft(rest[1:], " \t")
+	} else {
+		// logfmt: request_id=<id>
+		if !strings.HasPrefix(rest, "=") {
+			return "", false
+		}
+		rest = rest[1:]
+	}
+
+	var id string
+	if strings.HasPrefix(rest, `"`) {
+		value, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return "", false
+		}
+		if id, err = strconv.Unquote(value); err != nil {
+			return "", false
+		}
+	} else if quoted {
+		return "", false
+	} else {
+		id = rest
+		if i := strings.IndexAny(rest, " \t"); i >= 0 {
+			id = rest[:i]
+		}
+	}
+
+	if ValidateRequestID(id) != nil {
+		return "", false
+	}
+	return id, true
+}
+
+// isKeyChar reports whether c can be part of a log key, so request_id preceded by it is a longer key
+func isKeyChar(c byte) bool {
+	return c == '_' || c == '.' || c == '-' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
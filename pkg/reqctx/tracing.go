@@ -0,0 +1,50 @@
+package reqctx
+
+import "context"
+
+// TracingValues are the core tracing identifiers of a request, stored together under one context key
+// Every field is optional, empty means not set.
+type TracingValues struct {
+	RequestID       string
+	CorrelationID   string
+	ParentRequestID string
+	TenantID        string
+}
+
+// tracingValue holds the TracingValues of a request
+// ContextWithRequestID, ContextWithCorrelationID, ContextWithParentRequestID and ContextWithTenantID
+// all update it, so reading any of them is a single lookup however many were set.
+var tracingValue = NewContextValue[TracingValues]("tracing")
+
+// ContextWithTracing stores the non-empty fields of values in ctx, other fields keep the values of ctx
+// It adds one context node for all of them, instead of one per ContextWith* call. Use it where
+// several identifiers are set at once, like middlewares and message consumers.
+//
+// Usage:
+//
+//	ctx = reqctx.ContextWithTracing(ctx, reqctx.TracingValues{
+//		RequestID:     msg.RequestID,
+//		CorrelationID: msg.CorrelationID,
+//		TenantID:      msg.TenantID,
+//	})
+func ContextWithTracing(ctx context.Context, values TracingValues) context.Context {
+	current := tracingValue.Value(ctx)
+	if values.RequestID != "" {
+		current.RequestID = values.RequestID
+	}
+	if values.CorrelationID != "" {
+		current.CorrelationID = values.CorrelationID
+	}
+	if values.ParentRequestID != "" {
+		current.ParentRequestID = values.ParentRequestID
+	}
+	if values.TenantID != "" {
+		current.TenantID = values.TenantID
+	}
+	return tracingValue.With(ctx, current)
+}
+
+// TracingFromContext returns the tracing identifiers stored in ctx, missing ones are empty
+func TracingFromContext(ctx context.Context) TracingValues {
+	return tracingValue.Value(ctx)
+}
@@ -0,0 +1,69 @@
+package reqctx
+
+import (
+	"context"
+	"testing"
+)
+
+type benchKey struct{}
+
+// IDs for the benchmarks, variables so storing them isn't optimized like constants
+var (
+	benchRequestID     = "0f8fad5b-d9cb-469f-a165-70867728950e"
+	benchCorrelationID = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	benchParentID      = "16fd2706-8baf-433b-82eb-8c7fada847da"
+	benchTenantID      = "acme"
+)
+
+func BenchmarkContextWithTracingSetters(b *testing.B) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c := ContextWithRequestID(ctx, benchRequestID)
+		c = ContextWithCorrelationID(c, benchCorrelationID)
+		c = ContextWithParentRequestID(c, benchParentID)
+		_ = ContextWithTenantID(c, benchTenantID)
+	}
+}
+
+func BenchmarkExtractContext(b *testing.B) {
+	carrier := MapCarrier{
+		HeaderRequestID:       benchRequestID,
+		HeaderCorrelationID:   benchCorrelationID,
+		HeaderParentRequestID: benchParentID,
+		HeaderTenantID:        benchTenantID,
+	}
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = ExtractContext(ctx, carrier)
+	}
+}
+
+func BenchmarkTracingLookup(b *testing.B) {
+	ctx := ContextWithTenantID(ContextWithParentRequestID(ContextWithCorrelationID(
+		ContextWithRequestID(context.Background(), benchRequestID), benchCorrelationID), benchParentID), benchTenantID)
+	for i := 0; i < 5; i++ {
+		ctx = context.WithValue(ctx, benchKey{}, i)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = RequestIDFromContext(ctx)
+		_ = GetCorrelationIDFromContext(ctx)
+		_, _ = ParentRequestIDFromContext(ctx)
+		_, _ = TenantIDFromContext(ctx)
+	}
+}
+
+func BenchmarkContextWithTracing(b *testing.B) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = ContextWithTracing(ctx, TracingValues{
+			RequestID:       benchRequestID,
+			CorrelationID:   benchCorrelationID,
+			ParentRequestID: benchParentID,
+			TenantID:        benchTenantID,
+		})
+	}
+}
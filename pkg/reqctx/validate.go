@@ -0,0 +1,72 @@
+package reqctx
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// DefaultMaxRequestIDLength is the default maximum length of an accepted request ID
+const DefaultMaxRequestIDLength = 128
+
+var (
+	// ErrEmptyRequestID is returned when an empty request ID is supplied
+	ErrEmptyRequestID = errors.New("reqctx: empty request id")
+
+	// ErrRequestIDTooLong is returned when a request ID exceeds the maximum length
+	ErrRequestIDTooLong = errors.New("reqctx: request id too long")
+
+	// ErrInvalidRequestID is returned when a request ID contains CR/LF, spaces or non-printable characters
+	ErrInvalidRequestID = errors.New("reqctx: request id contains invalid characters")
+)
+
+var maxRequestIDLength atomic.Int64
+
+func init() {
+	maxRequestIDLength.Store(DefaultMaxRequestIDLength)
+}
+
+// SetMaxRequestIDLength changes the maximum accepted request ID length
+// Values <= 0 restore DefaultMaxRequestIDLength. Safe for concurrent use.
+func SetMaxRequestIDLength(n int) {
+	if n <= 0 {
+		n = DefaultMaxRequestIDLength
+	}
+	maxRequestIDLength.Store(int64(n))
+}
+
+// ValidateRequestID checks that id is safe to trust and echo in headers
+// Only visible ASCII characters are allowed. The returned error is one of
+// ErrEmptyRequestID, ErrRequestIDTooLong or ErrInvalidRequestID.
+//
+// Usage:
+//
+//	if err := reqctx.ValidateRequestID(id); err != nil {
+//		log.Warn("request id replaced", "reason", err)
+//	}
+func ValidateRequestID(id string) error {
+	if id == "" {
+		return ErrEmptyRequestID
+	}
+	if int64(len(id)) > maxRequestIDLength.Load() {
+		return ErrRequestIDTooLong
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return ErrInvalidRequestID
+		}
+	}
+	return nil
+}
+
+// SanitizeHeaderValue removes CR, LF and other control characters from v
+// Use it before echoing any externally influenced value in a response header.
+func SanitizeHeaderValue(v string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, v)
+}
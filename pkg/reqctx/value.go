@@ -0,0 +1,58 @@
+package reqctx
+
+import "context"
+
+// ContextValue is a typed context key declared once per value
+// Each NewContextValue call creates a distinct key, so two ContextValues never collide,
+// even with the same name or type. The TracingValues of this package are stored with one.
+//
+// Usage:
+//
+//	var Locale = reqctx.NewContextValue[string]("locale")
+//
+//	ctx = Locale.With(ctx, "de-DE")
+//	if locale, ok := Locale.Get(ctx); ok {
+//		// ...
+//	}
+type ContextValue[T any] struct {
+	key *valueKey
+}
+
+// valueKey is compared by pointer identity, the name is only for debugging
+type valueKey struct {
+	name string
+}
+
+// String implements fmt.Stringer, so context.Context String output shows the name
+func (k *valueKey) String() string {
+	return k.name
+}
+
+// NewContextValue declares a new typed context value, name is used in debug output only
+// Declare it as a package-level variable, values stored under one ContextValue[T] are
+// invisible to every other.
+func NewContextValue[T any](name string) ContextValue[T] {
+	return ContextValue[T]{key: &valueKey{name: name}}
+}
+
+// Name returns the name passed to NewContextValue
+func (v ContextValue[T]) Name() string {
+	return v.key.name
+}
+
+// With returns a copy of ctx carrying value
+func (v ContextValue[T]) With(ctx context.Context, value T) context.Context {
+	return context.WithValue(ctx, v.key, value)
+}
+
+// Get returns the value stored in ctx and whether it was found
+func (v ContextValue[T]) Get(ctx context.Context) (T, bool) {
+	value, ok := ctx.Value(v.key).(T)
+	return value, ok
+}
+
+// Value returns the value stored in ctx, or the zero value of T if missing
+func (v ContextValue[T]) Value(ctx context.Context) T {
+	value, _ := v.Get(ctx)
+	return value
+}
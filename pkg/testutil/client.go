@@ -0,0 +1,61 @@
+package testutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+)
+
+// TestClient returns a client for server that propagates tracing headers from ctx
+// Requests with a relative URL such as "/orders" are sent to server.URL. Requests whose own
+// context carries a request ID propagate that one instead of ctx. server counts as an internal host,
+// so it receives all tracing headers. TLS servers are supported
+// through server.Client().
+//
+// Usage:
+//
+//	server := httptest.NewServer(router)
+//	defer server.Close()
+//
+//	ctx := httputil.ContextWithRequestID(context.Background(), "test-request-id")
+//	resp, err := testutil.TestClient(server, ctx).Get("/orders")
+//	assert.Equal(t, "test-request-id", resp.Header.Get(httputil.HeaderRequestID))
+func TestClient(server *httptest.Server, ctx context.Context) *http.Client {
+	base, err := url.Parse(server.URL)
+	if err != nil {
+		panic("testutil: invalid server URL: " + err.Error())
+	}
+
+	client := server.Client()
+	client.Transport = &testTransport{
+		base: base,
+		ctx:  ctx,
+		next: &httputil.PropagatingTransport{Base: client.Transport, InternalHosts: []string{base.Hostname()}},
+	}
+	return client
+}
+
+// testTransport resolves relative URLs against the test server and supplies the trace context
+type testTransport struct {
+	base *url.URL
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *testTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqCtx := req.Context()
+	if _, ok := httputil.RequestIDFromContext(reqCtx); !ok {
+		reqCtx = httputil.WithTracingFrom(reqCtx, t.ctx)
+	}
+
+	req = req.Clone(reqCtx)
+	if req.URL.Host == "" {
+		req.URL = t.base.ResolveReference(req.URL)
+		req.Host = req.URL.Host
+	}
+	return t.next.RoundTrip(req)
+}
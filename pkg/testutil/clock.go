@@ -0,0 +1,42 @@
+package testutil
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is an httputil.Clock that only moves when told to
+// Safe for concurrent use.
+//
+// Usage:
+//
+//	clock := testutil.NewFakeClock(time.Unix(0, 0))
+//	router.Use(httputil.AccessLogMiddlewareWithConfig(httputil.AccessLogConfig{Logger: logger, Clock: clock}))
+//	router.GET("/slow", func(c *gin.Context) {
+//		clock.Advance(250 * time.Millisecond)
+//		c.Status(http.StatusOK)
+//	})
+//	// the access log record has latency=250ms
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock set to now
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current fake time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the fake time forward by d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,28 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+)
+
+// WithFixedID makes httputil generate id for every new request ID until the test ends
+// The previous generator is restored via t.Cleanup. The generator is package-global,
+// so don't combine it with t.Parallel(). A prefix set by SetServicePrefix still applies.
+//
+// Usage:
+//
+//	testutil.WithFixedID(t, "fixed-id")
+//	router.ServeHTTP(w, httptest.NewRequest("GET", "/orders", nil))
+//	assert.Equal(t, "fixed-id", w.Header().Get(httputil.HeaderRequestID))
+func WithFixedID(t testing.TB, id string) {
+	t.Helper()
+
+	previous := httputil.GetIDGenerator()
+	httputil.SetIDGenerator(httputil.IDGeneratorFunc(func() string {
+		return id
+	}))
+	t.Cleanup(func() {
+		httputil.SetIDGenerator(previous)
+	})
+}
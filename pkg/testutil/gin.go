@@ -0,0 +1,40 @@
+// Package testutil provides helpers for testing code that uses httputil request tracing
+package testutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+)
+
+// NewTestContext builds a gin.Context in test mode seeded with a fixed request ID
+// The ID is set in gin.Context storage and in c.Request.Context(), so both
+// httputil.GetRequestID and httputil.GetRequestIDFromContext return it.
+//
+// Usage:
+//
+//	c, w := testutil.NewTestContext("test-request-id")
+//	handler.CreateOrder(c)
+//	assert.Equal(t, "test-request-id", w.Header().Get(httputil.HeaderRequestID))
+func NewTestContext(requestID string) (*gin.Context, *httptest.ResponseRecorder) {
+	return NewTestContextWithContext(context.Background(), requestID)
+}
+
+// NewTestContextWithContext is NewTestContext with ctx as the parent of the request context
+// Use it to seed other values (deadline, tenant, ...) the handler reads from the request context
+func NewTestContextWithContext(ctx context.Context, requestID string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request = req.WithContext(httputil.ContextWithRequestID(ctx, requestID))
+	c.Set(httputil.RequestIDKey, requestID)
+
+	return c, w
+}
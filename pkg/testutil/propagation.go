@@ -0,0 +1,110 @@
+package testutil
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+)
+
+const (
+	// PropagationRequestID is the request ID AssertPropagation expects to arrive unchanged
+	PropagationRequestID = "testutil-request-id"
+
+	// PropagationCorrelationID is the correlation ID AssertPropagation expects to arrive unchanged
+	PropagationCorrelationID = "testutil-correlation-id"
+)
+
+// AssertPropagation checks that requests sent through rt carry the tracing IDs of their context
+// It starts a recording server and sends two requests through rt:
+//   - a context with PropagationRequestID and PropagationCorrelationID: both must arrive unchanged
+//   - a context without IDs: a valid request ID must be generated and sent as the correlation ID too
+//
+// Failures are reported with t.Errorf. A nil rt checks a PropagatingTransport treating the recording
+// server as internal. Pass the transport of the client under test, e.g. one with retries or an SDK
+// wrapper around it; it must treat loopback hosts as internal, e.g. with WithInternalHosts("127.0.0.1").
+//
+// Usage:
+//
+//	func TestClientPropagates(t *testing.T) {
+//		client := httputil.NewTracingClient(
+//			httputil.WithInternalHosts("127.0.0.1"),
+//			httputil.WithRetry(httputil.RetryOptions{MaxAttempts: 3}),
+//		)
+//		testutil.AssertPropagation(t, client.Transport)
+//	}
+func AssertPropagation(t testing.TB, rt http.RoundTripper) {
+	t.Helper()
+
+	var (
+		mu  sync.Mutex
+		got http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got = r.Header.Clone()
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if rt == nil {
+		host, _, _ := net.SplitHostPort(server.Listener.Addr().String())
+		rt = &httputil.PropagatingTransport{InternalHosts: []string{host}}
+	}
+
+	send := func(ctx context.Context) (http.Header, bool) {
+		t.Helper()
+
+		mu.Lock()
+		got = nil
+		mu.Unlock()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Errorf("testutil: build request: %v", err)
+			return nil, false
+		}
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Errorf("testutil: round trip: %v", err)
+			return nil, false
+		}
+		resp.Body.Close()
+
+		mu.Lock()
+		defer mu.Unlock()
+		if got == nil {
+			t.Errorf("testutil: request did not reach the recording server")
+			return nil, false
+		}
+		return got, true
+	}
+
+	ctx := httputil.ContextWithCorrelationID(
+		httputil.ContextWithRequestID(context.Background(), PropagationRequestID),
+		PropagationCorrelationID,
+	)
+	if h, ok := send(ctx); ok {
+		if id := h.Get(httputil.HeaderRequestID); id != PropagationRequestID {
+			t.Errorf("testutil: preserved request: %s = %q, want %q", httputil.HeaderRequestID, id, PropagationRequestID)
+		}
+		if id := h.Get(httputil.HeaderCorrelationID); id != PropagationCorrelationID {
+			t.Errorf("testutil: preserved request: %s = %q, want %q", httputil.HeaderCorrelationID, id, PropagationCorrelationID)
+		}
+	}
+
+	if h, ok := send(context.Background()); ok {
+		requestID := h.Get(httputil.HeaderRequestID)
+		if err := httputil.ValidateRequestID(requestID); err != nil {
+			t.Errorf("testutil: generated request: %s = %q: %v", httputil.HeaderRequestID, requestID, err)
+		}
+		if id := h.Get(httputil.HeaderCorrelationID); id != requestID {
+			t.Errorf("testutil: generated request: %s = %q, want the request ID %q", httputil.HeaderCorrelationID, id, requestID)
+		}
+	}
+}
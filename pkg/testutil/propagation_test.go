@@ -0,0 +1,37 @@
+package testutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+)
+
+func TestAssertPropagation(t *testing.T) {
+	t.Run("default transport", func(t *testing.T) {
+		AssertPropagation(t, nil)
+	})
+	t.Run("tracing client with loopback internal", func(t *testing.T) {
+		client := httputil.NewTracingClient(httputil.WithInternalHosts("127.0.0.1"))
+		AssertPropagation(t, client.Transport)
+	})
+}
+
+func TestTestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(httputil.HeaderCorrelationID, r.Header.Get(httputil.HeaderCorrelationID))
+	}))
+	defer server.Close()
+
+	ctx := httputil.ContextWithCorrelationID(httputil.ContextWithRequestID(context.Background(), "req-1"), "corr-1")
+	resp, err := TestClient(server, ctx).Get("/orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get(httputil.HeaderCorrelationID); got != "corr-1" {
+		t.Errorf("%s = %q, want corr-1", httputil.HeaderCorrelationID, got)
+	}
+}
@@ -0,0 +1,42 @@
+// Package zaputil provides go.uber.org/zap counterparts of the httputil slog helpers
+// It is a separate package so services not using zap don't depend on it
+package zaputil
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/TRAD3R/common/pkg/httputil"
+)
+
+// ZapFieldsFromContext returns request_id and correlation_id from ctx as zap fields
+// IDs missing in ctx are not added and never generated
+//
+// Usage:
+//
+//	logger.Info("order created", append(zaputil.ZapFieldsFromContext(ctx), zap.String("order_id", id))...)
+func ZapFieldsFromContext(ctx context.Context) []zap.Field {
+	var fields []zap.Field
+	if requestID, ok := httputil.RequestIDFromContext(ctx); ok {
+		fields = append(fields, zap.String(httputil.LogKeyRequestID, requestID))
+	}
+	if correlationID := httputil.GetCorrelationIDFromContext(ctx); correlationID != "" {
+		fields = append(fields, zap.String(httputil.LogKeyCorrelationID, correlationID))
+	}
+	return fields
+}
+
+// WithContext returns a child of logger with request_id and correlation_id fields from ctx
+//
+// Usage:
+//
+//	log := zaputil.WithContext(h.log, ctx)
+//	log.Info("order created")
+func WithContext(logger *zap.Logger, ctx context.Context) *zap.Logger {
+	fields := ZapFieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return logger
+	}
+	return logger.With(fields...)
+}